@@ -0,0 +1,116 @@
+package models
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/hamba/avro/v2"
+)
+
+// CodecAttributeName and SchemaIdAttributeName are the reserved message
+// attributes a producer sets to tag a message body with the codec (and,
+// for schema-bound codecs like Avro, the registry subject) it was encoded
+// with. Consumers use them to auto-decode via DecodeMessage.
+const (
+	CodecAttributeName    = "X-SmoothMQ-Codec"
+	SchemaIdAttributeName = "X-SmoothMQ-SchemaId"
+)
+
+// Codec marshals and unmarshals message bodies for a specific wire format.
+type Codec interface {
+	Name() string
+	Marshal(v any) ([]byte, error)
+	Unmarshal(data []byte, v any) error
+}
+
+// CodecRegistry looks codecs up by name (e.g. "json", "avro"). Additional
+// codecs, such as a protobuf one, can be added with Register once a concrete
+// implementation exists; none is bundled here yet.
+type CodecRegistry struct {
+	codecs map[string]Codec
+}
+
+// NewCodecRegistry returns a registry pre-populated with the JSON codec.
+// It is only used for messages that carry a codec attribute naming it.
+func NewCodecRegistry() *CodecRegistry {
+	r := &CodecRegistry{codecs: make(map[string]Codec)}
+	r.Register(JSONCodec{})
+	return r
+}
+
+func (r *CodecRegistry) Register(codec Codec) {
+	r.codecs[codec.Name()] = codec
+}
+
+func (r *CodecRegistry) Get(name string) (Codec, bool) {
+	codec, ok := r.codecs[name]
+	return codec, ok
+}
+
+// JSONCodec is the default, schema-less codec.
+type JSONCodec struct{}
+
+func (JSONCodec) Name() string { return "json" }
+
+func (JSONCodec) Marshal(v any) ([]byte, error) { return json.Marshal(v) }
+
+func (JSONCodec) Unmarshal(data []byte, v any) error { return json.Unmarshal(data, v) }
+
+// AvroCodec binds Marshal/Unmarshal to a single parsed Avro schema, as
+// returned by the schema registry for a given subject.
+type AvroCodec struct {
+	schema avro.Schema
+}
+
+// NewAvroCodec parses schemaJSON once so repeated Marshal/Unmarshal calls
+// don't pay the parsing cost per message.
+func NewAvroCodec(schemaJSON string) (*AvroCodec, error) {
+	schema, err := avro.Parse(schemaJSON)
+	if err != nil {
+		return nil, fmt.Errorf("parsing avro schema: %w", err)
+	}
+	return &AvroCodec{schema: schema}, nil
+}
+
+func (c *AvroCodec) Name() string { return "avro" }
+
+func (c *AvroCodec) Marshal(v any) ([]byte, error) { return avro.Marshal(c.schema, v) }
+
+func (c *AvroCodec) Unmarshal(data []byte, v any) error { return avro.Unmarshal(c.schema, data, v) }
+
+// DecodeMessage decodes body into a generic map using the codec (and, if
+// present, the schema) tagged on kv. It returns false when the message
+// carries no codec attribute, an unregistered codec, or fails to decode.
+func DecodeMessage(registry *CodecRegistry, schemas SchemaRegistry, tenantId int64, kv map[string]string, body []byte) (map[string]any, bool) {
+	codecName, ok := kv[CodecAttributeName]
+	if !ok {
+		return nil, false
+	}
+
+	// A schema-bound codec (e.g. Avro) is constructed fresh from the
+	// registered schema rather than looked up by name, since its Marshal/
+	// Unmarshal are bound to one parsed schema.
+	var codec Codec
+	if subject, ok := kv[SchemaIdAttributeName]; ok && schemas != nil && codecName == "avro" {
+		if schema, err := schemas.Latest(tenantId, subject); err == nil {
+			if avroCodec, err := NewAvroCodec(schema.Definition); err == nil {
+				codec = avroCodec
+			}
+		}
+	}
+
+	if codec == nil {
+		var ok bool
+		codec, ok = registry.Get(codecName)
+		if !ok {
+			return nil, false
+		}
+	}
+
+	var decoded map[string]any
+	if err := codec.Unmarshal(body, &decoded); err != nil {
+		return nil, false
+	}
+
+	return decoded, true
+}
@@ -0,0 +1,60 @@
+package models
+
+import "testing"
+
+func TestDecodeMessageJSON(t *testing.T) {
+	registry := NewCodecRegistry()
+
+	codec, _ := registry.Get("json")
+	body, err := codec.Marshal(map[string]any{"id": "abc"})
+	if err != nil {
+		t.Fatalf("Marshal: %v", err)
+	}
+
+	kv := map[string]string{CodecAttributeName: "json"}
+	decoded, ok := DecodeMessage(registry, nil, 1, kv, body)
+	if !ok {
+		t.Fatal("expected DecodeMessage to decode a json-tagged message")
+	}
+	if decoded["id"] != "abc" {
+		t.Fatalf("expected decoded[\"id\"]=abc, got %+v", decoded)
+	}
+}
+
+func TestDecodeMessageMissingCodecAttribute(t *testing.T) {
+	registry := NewCodecRegistry()
+	if _, ok := DecodeMessage(registry, nil, 1, map[string]string{}, []byte("{}")); ok {
+		t.Fatal("expected DecodeMessage to refuse a message with no codec attribute")
+	}
+}
+
+func TestDecodeMessageUsesRegisteredAvroSchema(t *testing.T) {
+	registry := NewCodecRegistry()
+	schemas := NewMemorySchemaRegistry()
+
+	schemaJSON := `{"type":"record","name":"Order","fields":[{"name":"id","type":"string"}]}`
+	if _, err := schemas.Register(1, "orders", "avro", schemaJSON); err != nil {
+		t.Fatalf("Register: %v", err)
+	}
+
+	avroCodec, err := NewAvroCodec(schemaJSON)
+	if err != nil {
+		t.Fatalf("NewAvroCodec: %v", err)
+	}
+	body, err := avroCodec.Marshal(map[string]any{"id": "abc"})
+	if err != nil {
+		t.Fatalf("Marshal: %v", err)
+	}
+
+	kv := map[string]string{
+		CodecAttributeName:    "avro",
+		SchemaIdAttributeName: "orders",
+	}
+	decoded, ok := DecodeMessage(registry, schemas, 1, kv, body)
+	if !ok {
+		t.Fatal("expected DecodeMessage to decode an avro-tagged message via the registered schema")
+	}
+	if decoded["id"] != "abc" {
+		t.Fatalf("expected decoded[\"id\"]=abc, got %+v", decoded)
+	}
+}
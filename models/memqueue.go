@@ -0,0 +1,630 @@
+package models
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+var ErrQueueNotFound = errors.New("queue not found")
+var ErrMessageNotFound = errors.New("message not found")
+
+type queueKey struct {
+	tenantId int64
+	queue    string
+}
+
+// message is MemoryQueue's internal record; Message is the trimmed-down
+// view handed back to callers.
+type message struct {
+	id           int64
+	body         []byte
+	kv           map[string]string
+	groupId      string
+	visibleAt    time.Time
+	receiveCount int64
+	deleted      bool
+}
+
+type queueRecord struct {
+	attributes map[string]string
+	messages   map[int64]*message
+	nextId     int64
+	sequence   int64
+	// dedup tracks MessageDeduplicationId -> the message it originally
+	// produced, for the 5-minute FIFO dedup window.
+	dedup map[string]dedupEntry
+}
+
+// dedupEntry is what EnqueueFifo remembers about a MessageDeduplicationId
+// until expiresAt, so a retried send within the window returns the
+// original message instead of enqueuing a duplicate.
+type dedupEntry struct {
+	messageId      int64
+	sequenceNumber string
+	expiresAt      time.Time
+}
+
+// fifoDedupWindow mirrors SQS FIFO's 5-minute deduplication interval.
+const fifoDedupWindow = 5 * time.Minute
+
+// notifier lets Subscribe hand out a single channel per tenant/queue that
+// every long-poll waiter selects on; Enqueue closes and replaces it, waking
+// all of them at once. This keeps fan-out bounded to one channel per queue
+// rather than one per waiter.
+type notifier struct {
+	mu sync.Mutex
+	ch chan struct{}
+}
+
+func newNotifier() *notifier {
+	return &notifier{ch: make(chan struct{})}
+}
+
+func (n *notifier) wait() <-chan struct{} {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	return n.ch
+}
+
+func (n *notifier) broadcast() {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	close(n.ch)
+	n.ch = make(chan struct{})
+}
+
+// MemoryQueue is an in-memory Queue implementation, used for tests and as a
+// reference for what a persistent backend needs to do; it does not retain
+// data across process restarts.
+type MemoryQueue struct {
+	mu        sync.Mutex
+	queues    map[queueKey]*queueRecord
+	notifiers map[queueKey]*notifier
+}
+
+func NewMemoryQueue() *MemoryQueue {
+	return &MemoryQueue{
+		queues:    make(map[queueKey]*queueRecord),
+		notifiers: make(map[queueKey]*notifier),
+	}
+}
+
+func (m *MemoryQueue) notifierFor(key queueKey) *notifier {
+	n, ok := m.notifiers[key]
+	if !ok {
+		n = newNotifier()
+		m.notifiers[key] = n
+	}
+	return n
+}
+
+func (m *MemoryQueue) CreateQueue(tenantId int64, name string, attributes map[string]string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	key := queueKey{tenantId, name}
+	if _, ok := m.queues[key]; ok {
+		return nil
+	}
+
+	attrsCopy := make(map[string]string, len(attributes))
+	for k, v := range attributes {
+		attrsCopy[k] = v
+	}
+
+	m.queues[key] = &queueRecord{
+		attributes: attrsCopy,
+		messages:   make(map[int64]*message),
+		dedup:      make(map[string]dedupEntry),
+	}
+	return nil
+}
+
+func (m *MemoryQueue) DeleteQueue(tenantId int64, name string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	delete(m.queues, queueKey{tenantId, name})
+	return nil
+}
+
+func (m *MemoryQueue) ListQueues(tenantId int64) ([]string, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	queues := make([]string, 0)
+	for key := range m.queues {
+		if key.tenantId == tenantId {
+			queues = append(queues, key.queue)
+		}
+	}
+	return queues, nil
+}
+
+func (m *MemoryQueue) Enqueue(tenantId int64, queue, body string, kv map[string]string, delaySeconds, visibilityTimeout int) (int64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	record, ok := m.queues[queueKey{tenantId, queue}]
+	if !ok {
+		return 0, ErrQueueNotFound
+	}
+
+	record.nextId++
+	id := record.nextId
+
+	record.messages[id] = &message{
+		id:        id,
+		body:      []byte(body),
+		kv:        kv,
+		visibleAt: time.Now().Add(time.Duration(delaySeconds) * time.Second),
+	}
+
+	m.notifierFor(queueKey{tenantId, queue}).broadcast()
+
+	return id, nil
+}
+
+func (m *MemoryQueue) EnqueueBatch(tenantId int64, queue string, entries []EnqueueBatchEntry) ([]int64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	record, ok := m.queues[queueKey{tenantId, queue}]
+	if !ok {
+		return nil, ErrQueueNotFound
+	}
+
+	// Assign ids and insert every entry under a single lock so a batch is
+	// never interleaved with a concurrent Enqueue/Dequeue on the same queue.
+	ids := make([]int64, len(entries))
+	for i, entry := range entries {
+		record.nextId++
+		id := record.nextId
+		ids[i] = id
+
+		record.messages[id] = &message{
+			id:        id,
+			body:      []byte(entry.Body),
+			kv:        entry.KeyValues,
+			visibleAt: time.Now().Add(time.Duration(entry.DelaySeconds) * time.Second),
+		}
+	}
+
+	if len(entries) > 0 {
+		m.notifierFor(queueKey{tenantId, queue}).broadcast()
+	}
+
+	return ids, nil
+}
+
+// EnqueueFifo enqueues a FIFO message, returning the original message's id
+// and sequence number instead of enqueuing again if dedupId was already
+// seen within the dedup window.
+func (m *MemoryQueue) EnqueueFifo(tenantId int64, queue, body string, kv map[string]string, delaySeconds, visibilityTimeout int, groupId, dedupId string) (int64, string, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	record, ok := m.queues[queueKey{tenantId, queue}]
+	if !ok {
+		return 0, "", ErrQueueNotFound
+	}
+
+	id, sequenceNumber := m.enqueueFifoLocked(record, body, kv, delaySeconds, groupId, dedupId)
+	m.notifierFor(queueKey{tenantId, queue}).broadcast()
+	return id, sequenceNumber, nil
+}
+
+// EnqueueFifoBatch is EnqueueFifo's SendMessageBatch counterpart: every
+// entry is deduped/inserted while holding the lock once, so a batch can't
+// be interleaved with a concurrent send to the same FIFO queue.
+func (m *MemoryQueue) EnqueueFifoBatch(tenantId int64, queue string, entries []EnqueueFifoBatchEntry) ([]EnqueueFifoBatchResult, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	record, ok := m.queues[queueKey{tenantId, queue}]
+	if !ok {
+		return nil, ErrQueueNotFound
+	}
+
+	results := make([]EnqueueFifoBatchResult, len(entries))
+	for i, entry := range entries {
+		id, sequenceNumber := m.enqueueFifoLocked(record, entry.Body, entry.KeyValues, entry.DelaySeconds, entry.GroupId, entry.DedupId)
+		results[i] = EnqueueFifoBatchResult{MessageId: id, SequenceNumber: sequenceNumber}
+	}
+
+	if len(entries) > 0 {
+		m.notifierFor(queueKey{tenantId, queue}).broadcast()
+	}
+
+	return results, nil
+}
+
+// enqueueFifoLocked does the dedup-or-insert work shared by EnqueueFifo and
+// EnqueueFifoBatch. Callers must hold m.mu and have already resolved record.
+func (m *MemoryQueue) enqueueFifoLocked(record *queueRecord, body string, kv map[string]string, delaySeconds int, groupId, dedupId string) (int64, string) {
+	now := time.Now()
+	if entry, ok := record.dedup[dedupId]; ok && entry.expiresAt.After(now) {
+		return entry.messageId, entry.sequenceNumber
+	}
+
+	record.nextId++
+	id := record.nextId
+	record.sequence++
+	sequenceNumber := fmt.Sprintf("%020d", record.sequence)
+
+	record.messages[id] = &message{
+		id:        id,
+		body:      []byte(body),
+		kv:        kv,
+		groupId:   groupId,
+		visibleAt: now.Add(time.Duration(delaySeconds) * time.Second),
+	}
+	record.dedup[dedupId] = dedupEntry{messageId: id, sequenceNumber: sequenceNumber, expiresAt: now.Add(fifoDedupWindow)}
+
+	return id, sequenceNumber
+}
+
+func (m *MemoryQueue) Dequeue(tenantId int64, queue string, max int) ([]Message, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	record, ok := m.queues[queueKey{tenantId, queue}]
+	if !ok {
+		return nil, ErrQueueNotFound
+	}
+
+	now := time.Now()
+	visibilityTimeout := defaultLeaseSeconds(record.attributes)
+
+	// lockedGroups holds every MessageGroupId with a message currently
+	// in flight (leased or not yet due), so at most one message per group
+	// is ever handed out at a time, and in ascending id order within an
+	// unlocked group.
+	lockedGroups := make(map[string]bool)
+	for _, msg := range record.messages {
+		if msg.groupId != "" && !msg.deleted && msg.visibleAt.After(now) {
+			lockedGroups[msg.groupId] = true
+		}
+	}
+
+	ids := make([]int64, 0, len(record.messages))
+	for id := range record.messages {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+
+	results := make([]Message, 0, max)
+	for _, id := range ids {
+		if len(results) >= max {
+			break
+		}
+
+		msg := record.messages[id]
+		if msg.deleted || msg.visibleAt.After(now) {
+			continue
+		}
+		if msg.groupId != "" && lockedGroups[msg.groupId] {
+			continue
+		}
+
+		msg.receiveCount++
+		msg.visibleAt = now.Add(time.Duration(visibilityTimeout) * time.Second)
+		if msg.groupId != "" {
+			lockedGroups[msg.groupId] = true
+		}
+
+		if m.redriveIfExceeded(tenantId, queue, record, msg) {
+			continue
+		}
+
+		results = append(results, Message{
+			ID:           msg.id,
+			Message:      msg.body,
+			KeyValues:    msg.kv,
+			GroupId:      msg.groupId,
+			ReceiveCount: msg.receiveCount,
+		})
+	}
+
+	return results, nil
+}
+
+// redrivePolicy mirrors the JSON SQS accepts for a queue's RedrivePolicy
+// attribute.
+type redrivePolicy struct {
+	DeadLetterTargetArn string `json:"deadLetterTargetArn"`
+	MaxReceiveCount     int    `json:"maxReceiveCount"`
+}
+
+// parseRedrivePolicy returns the queue's RedrivePolicy, or ok=false if the
+// queue has none set.
+func parseRedrivePolicy(attributes map[string]string) (redrivePolicy, bool) {
+	raw, ok := attributes["RedrivePolicy"]
+	if !ok || raw == "" {
+		return redrivePolicy{}, false
+	}
+
+	var policy redrivePolicy
+	if err := json.Unmarshal([]byte(raw), &policy); err != nil {
+		return redrivePolicy{}, false
+	}
+	return policy, true
+}
+
+// dlqTargetName extracts the queue name from a RedrivePolicy's
+// deadLetterTargetArn (e.g. "arn:aws:sqs:us-east-1:1:my-dlq" -> "my-dlq").
+func dlqTargetName(arn string) string {
+	tokens := strings.Split(arn, ":")
+	return tokens[len(tokens)-1]
+}
+
+// redriveIfExceeded moves msg to its queue's configured DLQ once its
+// ReceiveCount has exceeded maxReceiveCount, reporting whether it did so.
+// Callers must hold m.mu and have already incremented msg.receiveCount.
+func (m *MemoryQueue) redriveIfExceeded(tenantId int64, queue string, record *queueRecord, msg *message) bool {
+	policy, ok := parseRedrivePolicy(record.attributes)
+	if !ok || policy.MaxReceiveCount <= 0 || msg.receiveCount <= int64(policy.MaxReceiveCount) {
+		return false
+	}
+
+	dlqName := dlqTargetName(policy.DeadLetterTargetArn)
+	dlqKey := queueKey{tenantId, dlqName}
+	dlq, ok := m.queues[dlqKey]
+	if !ok {
+		return false
+	}
+
+	dlq.nextId++
+	dlq.messages[dlq.nextId] = &message{
+		id:        dlq.nextId,
+		body:      msg.body,
+		kv:        msg.kv,
+		visibleAt: time.Now(),
+	}
+	m.notifierFor(dlqKey).broadcast()
+
+	msg.deleted = true
+	delete(record.messages, msg.id)
+	return true
+}
+
+// defaultLeaseSeconds is the lease Dequeue grants a message: the queue's
+// VisibilityTimeout attribute, or 30 seconds when it is unset or not a
+// number. Dequeue takes no per-call override, so every dequeued message gets
+// this lease.
+func defaultLeaseSeconds(attributes map[string]string) int {
+	if seconds, err := strconv.Atoi(attributes["VisibilityTimeout"]); err == nil {
+		return seconds
+	}
+	return 30
+}
+
+func (m *MemoryQueue) Delete(tenantId int64, queue string, messageId int64) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	record, ok := m.queues[queueKey{tenantId, queue}]
+	if !ok {
+		return ErrQueueNotFound
+	}
+
+	msg, ok := record.messages[messageId]
+	if !ok {
+		return ErrMessageNotFound
+	}
+
+	msg.deleted = true
+	delete(record.messages, messageId)
+	return nil
+}
+
+// ErrReceiptHandleStale means a message has since been redelivered under a
+// newer receiveCount, so a DeleteLeased or ChangeVisibilityLeased referencing
+// the stale lease has no effect. A message that was already deleted yields
+// ErrMessageNotFound instead.
+var ErrReceiptHandleStale = errors.New("receipt handle no longer matches the message's current lease")
+
+// DeleteLeased deletes messageId only if it's still on receiveCount's
+// lease generation, so a delayed delete from a prior lease can't remove a
+// copy that was redelivered in the meantime.
+func (m *MemoryQueue) DeleteLeased(tenantId int64, queue string, messageId, receiveCount int64) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	record, ok := m.queues[queueKey{tenantId, queue}]
+	if !ok {
+		return ErrQueueNotFound
+	}
+
+	msg, ok := record.messages[messageId]
+	if !ok {
+		return ErrMessageNotFound
+	}
+	if msg.receiveCount != receiveCount {
+		return ErrReceiptHandleStale
+	}
+
+	msg.deleted = true
+	delete(record.messages, messageId)
+	return nil
+}
+
+// ChangeVisibilityLeased extends/shortens messageId's lease by seconds,
+// subject to the same receiveCount generation check as DeleteLeased.
+func (m *MemoryQueue) ChangeVisibilityLeased(tenantId int64, queue string, messageId, receiveCount int64, seconds int) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	record, ok := m.queues[queueKey{tenantId, queue}]
+	if !ok {
+		return ErrQueueNotFound
+	}
+
+	msg, ok := record.messages[messageId]
+	if !ok {
+		return ErrMessageNotFound
+	}
+	if msg.receiveCount != receiveCount {
+		return ErrReceiptHandleStale
+	}
+
+	msg.visibleAt = time.Now().Add(time.Duration(seconds) * time.Second)
+	return nil
+}
+
+func (m *MemoryQueue) DeleteBatch(tenantId int64, queue string, messageIds []int64) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	record, ok := m.queues[queueKey{tenantId, queue}]
+	if !ok {
+		return ErrQueueNotFound
+	}
+
+	for _, messageId := range messageIds {
+		if msg, ok := record.messages[messageId]; ok {
+			msg.deleted = true
+			delete(record.messages, messageId)
+		}
+	}
+
+	return nil
+}
+
+func (m *MemoryQueue) Peek(tenantId int64, queue string, messageId int64) *Message {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	record, ok := m.queues[queueKey{tenantId, queue}]
+	if !ok {
+		return nil
+	}
+
+	msg, ok := record.messages[messageId]
+	if !ok || msg.deleted {
+		return nil
+	}
+
+	return &Message{
+		ID:           msg.id,
+		Message:      msg.body,
+		KeyValues:    msg.kv,
+		GroupId:      msg.groupId,
+		ReceiveCount: msg.receiveCount,
+	}
+}
+
+func (m *MemoryQueue) Filter(tenantId int64, queue string, criteria FilterCriteria) []int64 {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	record, ok := m.queues[queueKey{tenantId, queue}]
+	if !ok {
+		return nil
+	}
+
+	ids := make([]int64, 0)
+	for id, msg := range record.messages {
+		if msg.deleted {
+			continue
+		}
+		if criteria.MessageID != 0 && criteria.MessageID != id {
+			continue
+		}
+
+		matched := true
+		for k, v := range criteria.KV {
+			if msg.kv[k] != v {
+				matched = false
+				break
+			}
+		}
+		if !matched {
+			continue
+		}
+
+		ids = append(ids, id)
+	}
+
+	return ids
+}
+
+func (m *MemoryQueue) Stats(tenantId int64, queue string) QueueStats {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	record, ok := m.queues[queueKey{tenantId, queue}]
+	if !ok {
+		return QueueStats{Counts: map[string]int{}}
+	}
+
+	now := time.Now()
+	counts := map[string]int{"Visible": 0, "InFlight": 0}
+	for _, msg := range record.messages {
+		if msg.deleted {
+			continue
+		}
+		if msg.visibleAt.After(now) {
+			counts["InFlight"]++
+		} else {
+			counts["Visible"]++
+		}
+	}
+
+	return QueueStats{
+		TotalMessages: counts["Visible"] + counts["InFlight"],
+		Counts:        counts,
+	}
+}
+
+func (m *MemoryQueue) GetAttributes(tenantId int64, queue string) map[string]string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	record, ok := m.queues[queueKey{tenantId, queue}]
+	if !ok {
+		return nil
+	}
+
+	attrsCopy := make(map[string]string, len(record.attributes))
+	for k, v := range record.attributes {
+		attrsCopy[k] = v
+	}
+	return attrsCopy
+}
+
+func (m *MemoryQueue) SetAttributes(tenantId int64, queue string, attributes map[string]string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	record, ok := m.queues[queueKey{tenantId, queue}]
+	if !ok {
+		return ErrQueueNotFound
+	}
+
+	if raw, ok := attributes["RedrivePolicy"]; ok && raw != "" {
+		policy, ok := parseRedrivePolicy(attributes)
+		if !ok || policy.MaxReceiveCount <= 0 || policy.DeadLetterTargetArn == "" {
+			return errors.New("InvalidAttributeValue: RedrivePolicy must set a deadLetterTargetArn and a positive maxReceiveCount")
+		}
+	}
+
+	for k, v := range attributes {
+		record.attributes[k] = v
+	}
+	return nil
+}
+
+func (m *MemoryQueue) Subscribe(tenantId int64, queue string) (<-chan struct{}, func()) {
+	m.mu.Lock()
+	n := m.notifierFor(queueKey{tenantId, queue})
+	m.mu.Unlock()
+
+	return n.wait(), func() {}
+}
@@ -0,0 +1,64 @@
+package models
+
+import "testing"
+
+func TestMemoryQueueEnqueueBatch(t *testing.T) {
+	q := NewMemoryQueue()
+	if err := q.CreateQueue(1, "orders", nil); err != nil {
+		t.Fatalf("CreateQueue: %v", err)
+	}
+
+	ids, err := q.EnqueueBatch(1, "orders", []EnqueueBatchEntry{
+		{Body: "one"},
+		{Body: "two"},
+		{Body: "three"},
+	})
+	if err != nil {
+		t.Fatalf("EnqueueBatch: %v", err)
+	}
+	if len(ids) != 3 {
+		t.Fatalf("expected 3 ids, got %d", len(ids))
+	}
+	if ids[0] == ids[1] || ids[1] == ids[2] {
+		t.Fatalf("expected distinct ids, got %v", ids)
+	}
+
+	messages, err := q.Dequeue(1, "orders", 10)
+	if err != nil {
+		t.Fatalf("Dequeue: %v", err)
+	}
+	if len(messages) != 3 {
+		t.Fatalf("expected 3 messages, got %d", len(messages))
+	}
+}
+
+func TestMemoryQueueDeleteBatch(t *testing.T) {
+	q := NewMemoryQueue()
+	if err := q.CreateQueue(1, "orders", nil); err != nil {
+		t.Fatalf("CreateQueue: %v", err)
+	}
+
+	ids, err := q.EnqueueBatch(1, "orders", []EnqueueBatchEntry{{Body: "one"}, {Body: "two"}})
+	if err != nil {
+		t.Fatalf("EnqueueBatch: %v", err)
+	}
+
+	if err := q.DeleteBatch(1, "orders", ids); err != nil {
+		t.Fatalf("DeleteBatch: %v", err)
+	}
+
+	messages, err := q.Dequeue(1, "orders", 10)
+	if err != nil {
+		t.Fatalf("Dequeue: %v", err)
+	}
+	if len(messages) != 0 {
+		t.Fatalf("expected no messages after DeleteBatch, got %d", len(messages))
+	}
+}
+
+func TestMemoryQueueEnqueueBatchUnknownQueue(t *testing.T) {
+	q := NewMemoryQueue()
+	if _, err := q.EnqueueBatch(1, "missing", []EnqueueBatchEntry{{Body: "one"}}); err != ErrQueueNotFound {
+		t.Fatalf("expected ErrQueueNotFound, got %v", err)
+	}
+}
@@ -0,0 +1,94 @@
+package models
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestMemoryQueueRedrivesAfterMaxReceiveCount(t *testing.T) {
+	q := NewMemoryQueue()
+	if err := q.CreateQueue(1, "dlq", nil); err != nil {
+		t.Fatalf("CreateQueue dlq: %v", err)
+	}
+
+	policy, err := json.Marshal(redrivePolicy{DeadLetterTargetArn: "arn:aws:sqs:us-east-1:1:dlq", MaxReceiveCount: 2})
+	if err != nil {
+		t.Fatalf("marshal policy: %v", err)
+	}
+	if err := q.CreateQueue(1, "orders", map[string]string{"RedrivePolicy": string(policy), "VisibilityTimeout": "0"}); err != nil {
+		t.Fatalf("CreateQueue orders: %v", err)
+	}
+
+	if _, err := q.Enqueue(1, "orders", "hello", nil, 0, 0); err != nil {
+		t.Fatalf("Enqueue: %v", err)
+	}
+
+	for i := 0; i < 2; i++ {
+		messages, err := q.Dequeue(1, "orders", 10)
+		if err != nil {
+			t.Fatalf("Dequeue: %v", err)
+		}
+		if len(messages) != 1 {
+			t.Fatalf("expected message to still be deliverable on attempt %d, got %d", i+1, len(messages))
+		}
+	}
+
+	// The 3rd receive exceeds maxReceiveCount=2, so it should redrive instead
+	// of being returned from the source queue.
+	messages, err := q.Dequeue(1, "orders", 10)
+	if err != nil {
+		t.Fatalf("Dequeue: %v", err)
+	}
+	if len(messages) != 0 {
+		t.Fatalf("expected message to be redriven to the DLQ, got %d messages still on orders", len(messages))
+	}
+
+	dlqMessages, err := q.Dequeue(1, "dlq", 10)
+	if err != nil {
+		t.Fatalf("Dequeue dlq: %v", err)
+	}
+	if len(dlqMessages) != 1 || string(dlqMessages[0].Message) != "hello" {
+		t.Fatalf("expected redriven message on dlq, got %+v", dlqMessages)
+	}
+}
+
+func TestMemoryQueueSetAttributesRejectsInvalidRedrivePolicy(t *testing.T) {
+	q := NewMemoryQueue()
+	if err := q.CreateQueue(1, "orders", nil); err != nil {
+		t.Fatalf("CreateQueue: %v", err)
+	}
+
+	if err := q.SetAttributes(1, "orders", map[string]string{"RedrivePolicy": "not json"}); err == nil {
+		t.Fatal("expected an error for an unparsable RedrivePolicy")
+	}
+
+	if err := q.SetAttributes(1, "orders", map[string]string{"RedrivePolicy": `{"deadLetterTargetArn":"arn:aws:sqs:us-east-1:1:dlq"}`}); err == nil {
+		t.Fatal("expected an error for a RedrivePolicy missing maxReceiveCount")
+	}
+}
+
+func TestMemoryQueueDeleteLeasedRejectsStaleReceiveCount(t *testing.T) {
+	q := NewMemoryQueue()
+	if err := q.CreateQueue(1, "orders", nil); err != nil {
+		t.Fatalf("CreateQueue: %v", err)
+	}
+	if _, err := q.Enqueue(1, "orders", "hello", nil, 0, 30); err != nil {
+		t.Fatalf("Enqueue: %v", err)
+	}
+
+	messages, err := q.Dequeue(1, "orders", 10)
+	if err != nil {
+		t.Fatalf("Dequeue: %v", err)
+	}
+	if len(messages) != 1 {
+		t.Fatalf("expected 1 message, got %d", len(messages))
+	}
+
+	if err := q.DeleteLeased(1, "orders", messages[0].ID, messages[0].ReceiveCount+1); err != ErrReceiptHandleStale {
+		t.Fatalf("expected ErrReceiptHandleStale, got %v", err)
+	}
+
+	if err := q.DeleteLeased(1, "orders", messages[0].ID, messages[0].ReceiveCount); err != nil {
+		t.Fatalf("DeleteLeased: %v", err)
+	}
+}
@@ -0,0 +1,108 @@
+package models
+
+import "testing"
+
+func TestMemoryQueueEnqueueFifoDedup(t *testing.T) {
+	q := NewMemoryQueue()
+	if err := q.CreateQueue(1, "orders.fifo", nil); err != nil {
+		t.Fatalf("CreateQueue: %v", err)
+	}
+
+	id1, seq1, err := q.EnqueueFifo(1, "orders.fifo", "hello", nil, 0, 30, "group-a", "dedup-1")
+	if err != nil {
+		t.Fatalf("EnqueueFifo: %v", err)
+	}
+
+	id2, seq2, err := q.EnqueueFifo(1, "orders.fifo", "hello again", nil, 0, 30, "group-a", "dedup-1")
+	if err != nil {
+		t.Fatalf("EnqueueFifo: %v", err)
+	}
+
+	if id1 != id2 || seq1 != seq2 {
+		t.Fatalf("expected a repeated dedup id to return the original message, got (%d,%s) vs (%d,%s)", id1, seq1, id2, seq2)
+	}
+
+	messages, err := q.Dequeue(1, "orders.fifo", 10)
+	if err != nil {
+		t.Fatalf("Dequeue: %v", err)
+	}
+	if len(messages) != 1 {
+		t.Fatalf("expected dedup to prevent a second message, got %d", len(messages))
+	}
+}
+
+func TestMemoryQueueFifoGroupOrdering(t *testing.T) {
+	q := NewMemoryQueue()
+	if err := q.CreateQueue(1, "orders.fifo", nil); err != nil {
+		t.Fatalf("CreateQueue: %v", err)
+	}
+
+	if _, _, err := q.EnqueueFifo(1, "orders.fifo", "first", nil, 0, 30, "group-a", "dedup-1"); err != nil {
+		t.Fatalf("EnqueueFifo: %v", err)
+	}
+	if _, _, err := q.EnqueueFifo(1, "orders.fifo", "second", nil, 0, 30, "group-a", "dedup-2"); err != nil {
+		t.Fatalf("EnqueueFifo: %v", err)
+	}
+
+	// Only the first message of group-a should be visible while it's leased.
+	messages, err := q.Dequeue(1, "orders.fifo", 10)
+	if err != nil {
+		t.Fatalf("Dequeue: %v", err)
+	}
+	if len(messages) != 1 {
+		t.Fatalf("expected only 1 in-flight message per group, got %d", len(messages))
+	}
+	if string(messages[0].Message) != "first" {
+		t.Fatalf("expected group order to hand out %q first, got %q", "first", messages[0].Message)
+	}
+
+	if err := q.Delete(1, "orders.fifo", messages[0].ID); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+
+	messages, err = q.Dequeue(1, "orders.fifo", 10)
+	if err != nil {
+		t.Fatalf("Dequeue: %v", err)
+	}
+	if len(messages) != 1 || string(messages[0].Message) != "second" {
+		t.Fatalf("expected %q to become visible after %q was deleted, got %+v", "second", "first", messages)
+	}
+}
+
+func TestMemoryQueueEnqueueFifoBatchDedupAndOrdering(t *testing.T) {
+	q := NewMemoryQueue()
+	if err := q.CreateQueue(1, "orders.fifo", nil); err != nil {
+		t.Fatalf("CreateQueue: %v", err)
+	}
+
+	results, err := q.EnqueueFifoBatch(1, "orders.fifo", []EnqueueFifoBatchEntry{
+		{Body: "first", GroupId: "group-a", DedupId: "dedup-1"},
+		{Body: "second", GroupId: "group-a", DedupId: "dedup-2"},
+		{Body: "first again", GroupId: "group-a", DedupId: "dedup-1"},
+	})
+	if err != nil {
+		t.Fatalf("EnqueueFifoBatch: %v", err)
+	}
+	if len(results) != 3 {
+		t.Fatalf("expected 3 results, got %d", len(results))
+	}
+	if results[0].MessageId != results[2].MessageId || results[0].SequenceNumber != results[2].SequenceNumber {
+		t.Fatalf("expected a repeated dedup id within the batch to reuse the original message, got %+v vs %+v", results[0], results[2])
+	}
+
+	// Only the first message of group-a should be visible while it's leased.
+	messages, err := q.Dequeue(1, "orders.fifo", 10)
+	if err != nil {
+		t.Fatalf("Dequeue: %v", err)
+	}
+	if len(messages) != 1 || string(messages[0].Message) != "first" {
+		t.Fatalf("expected group order to hand out %q first, got %+v", "first", messages)
+	}
+}
+
+func TestMemoryQueueEnqueueFifoBatchMissingQueue(t *testing.T) {
+	q := NewMemoryQueue()
+	if _, err := q.EnqueueFifoBatch(1, "missing.fifo", []EnqueueFifoBatchEntry{{Body: "x", DedupId: "d"}}); err != ErrQueueNotFound {
+		t.Fatalf("expected ErrQueueNotFound, got %v", err)
+	}
+}
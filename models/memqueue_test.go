@@ -0,0 +1,74 @@
+package models
+
+import (
+	"testing"
+	"time"
+)
+
+func TestMemoryQueueEnqueueDequeue(t *testing.T) {
+	q := NewMemoryQueue()
+
+	if err := q.CreateQueue(1, "orders", nil); err != nil {
+		t.Fatalf("CreateQueue: %v", err)
+	}
+
+	if _, err := q.Enqueue(1, "orders", "hello", nil, 0, 30); err != nil {
+		t.Fatalf("Enqueue: %v", err)
+	}
+
+	messages, err := q.Dequeue(1, "orders", 10)
+	if err != nil {
+		t.Fatalf("Dequeue: %v", err)
+	}
+	if len(messages) != 1 {
+		t.Fatalf("expected 1 message, got %d", len(messages))
+	}
+	if string(messages[0].Message) != "hello" {
+		t.Fatalf("expected body %q, got %q", "hello", messages[0].Message)
+	}
+	if messages[0].ReceiveCount != 1 {
+		t.Fatalf("expected ReceiveCount 1, got %d", messages[0].ReceiveCount)
+	}
+
+	// Still leased, so a second Dequeue should see nothing.
+	messages, err = q.Dequeue(1, "orders", 10)
+	if err != nil {
+		t.Fatalf("Dequeue: %v", err)
+	}
+	if len(messages) != 0 {
+		t.Fatalf("expected message to still be leased, got %d visible", len(messages))
+	}
+}
+
+func TestMemoryQueueSubscribeWakesOnEnqueue(t *testing.T) {
+	q := NewMemoryQueue()
+	if err := q.CreateQueue(1, "orders", nil); err != nil {
+		t.Fatalf("CreateQueue: %v", err)
+	}
+
+	notify, cancel := q.Subscribe(1, "orders")
+	defer cancel()
+
+	done := make(chan struct{})
+	go func() {
+		<-notify
+		close(done)
+	}()
+
+	if _, err := q.Enqueue(1, "orders", "hello", nil, 0, 30); err != nil {
+		t.Fatalf("Enqueue: %v", err)
+	}
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("Subscribe did not wake up after Enqueue")
+	}
+}
+
+func TestMemoryQueueDequeueUnknownQueue(t *testing.T) {
+	q := NewMemoryQueue()
+	if _, err := q.Dequeue(1, "missing", 10); err != ErrQueueNotFound {
+		t.Fatalf("expected ErrQueueNotFound, got %v", err)
+	}
+}
@@ -0,0 +1,71 @@
+package models
+
+import (
+	"errors"
+	"sync"
+)
+
+var ErrSchemaNotFound = errors.New("schema not found")
+
+type schemaKey struct {
+	tenantId int64
+	subject  string
+}
+
+// MemorySchemaRegistry is an in-memory SchemaRegistry, keyed by
+// (tenant, subject, version). It's the schema-registry counterpart to
+// MemoryQueue: a reference/testing backend that keeps schemas only in
+// memory, so they are lost on restart.
+type MemorySchemaRegistry struct {
+	mu      sync.Mutex
+	schemas map[schemaKey][]*Schema
+}
+
+func NewMemorySchemaRegistry() *MemorySchemaRegistry {
+	return &MemorySchemaRegistry{schemas: make(map[schemaKey][]*Schema)}
+}
+
+// Register appends a new version of subject, numbered one past whatever
+// version (if any) was registered before it.
+func (r *MemorySchemaRegistry) Register(tenantId int64, subject, codec, definition string) (*Schema, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	key := schemaKey{tenantId, subject}
+	versions := r.schemas[key]
+
+	schema := &Schema{
+		Tenant:     tenantId,
+		Subject:    subject,
+		Version:    len(versions) + 1,
+		Codec:      codec,
+		Definition: definition,
+	}
+	r.schemas[key] = append(versions, schema)
+
+	return schema, nil
+}
+
+func (r *MemorySchemaRegistry) Get(tenantId int64, subject string, version int) (*Schema, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	versions := r.schemas[schemaKey{tenantId, subject}]
+	if version < 1 || version > len(versions) {
+		return nil, ErrSchemaNotFound
+	}
+	return versions[version-1], nil
+}
+
+func (r *MemorySchemaRegistry) Latest(tenantId int64, subject string) (*Schema, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	versions := r.schemas[schemaKey{tenantId, subject}]
+	if len(versions) == 0 {
+		return nil, ErrSchemaNotFound
+	}
+	return versions[len(versions)-1], nil
+}
+
+var _ SchemaRegistry = (*MemorySchemaRegistry)(nil)
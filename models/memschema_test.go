@@ -0,0 +1,62 @@
+package models
+
+import "testing"
+
+func TestMemorySchemaRegistryRegisterIncrementsVersion(t *testing.T) {
+	r := NewMemorySchemaRegistry()
+
+	first, err := r.Register(1, "orders", "avro", `{"type":"record","name":"Order","fields":[]}`)
+	if err != nil {
+		t.Fatalf("Register: %v", err)
+	}
+	if first.Version != 1 {
+		t.Fatalf("expected the first registration to be version 1, got %d", first.Version)
+	}
+
+	second, err := r.Register(1, "orders", "avro", `{"type":"record","name":"Order","fields":[{"name":"id","type":"string"}]}`)
+	if err != nil {
+		t.Fatalf("Register: %v", err)
+	}
+	if second.Version != 2 {
+		t.Fatalf("expected the second registration to be version 2, got %d", second.Version)
+	}
+
+	latest, err := r.Latest(1, "orders")
+	if err != nil {
+		t.Fatalf("Latest: %v", err)
+	}
+	if latest.Version != 2 {
+		t.Fatalf("expected Latest to return version 2, got %d", latest.Version)
+	}
+
+	got, err := r.Get(1, "orders", 1)
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	if got.Definition != first.Definition {
+		t.Fatalf("expected Get(version=1) to return the first definition, got %q", got.Definition)
+	}
+}
+
+func TestMemorySchemaRegistryScopedByTenant(t *testing.T) {
+	r := NewMemorySchemaRegistry()
+
+	if _, err := r.Register(1, "orders", "json", "{}"); err != nil {
+		t.Fatalf("Register: %v", err)
+	}
+
+	if _, err := r.Latest(2, "orders"); err != ErrSchemaNotFound {
+		t.Fatalf("expected ErrSchemaNotFound for an unregistered tenant/subject, got %v", err)
+	}
+}
+
+func TestMemorySchemaRegistryUnknownVersion(t *testing.T) {
+	r := NewMemorySchemaRegistry()
+	if _, err := r.Register(1, "orders", "json", "{}"); err != nil {
+		t.Fatalf("Register: %v", err)
+	}
+
+	if _, err := r.Get(1, "orders", 2); err != ErrSchemaNotFound {
+		t.Fatalf("expected ErrSchemaNotFound for an unregistered version, got %v", err)
+	}
+}
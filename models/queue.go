@@ -0,0 +1,105 @@
+package models
+
+// Message is a single row handed back by Dequeue/Peek.
+type Message struct {
+	ID           int64
+	Message      []byte
+	KeyValues    map[string]string
+	GroupId      string
+	ReceiveCount int64
+}
+
+// QueueStats summarizes a queue's message counts, broken down by visibility
+// state (e.g. "Visible", "InFlight"); callers sum Counts for the total.
+type QueueStats struct {
+	TotalMessages int
+	Counts        map[string]int
+}
+
+// FilterCriteria narrows Filter's results. A zero MessageID means "don't
+// filter by ID"; KV pairs must all match a message's attributes.
+type FilterCriteria struct {
+	MessageID int64
+	KV        map[string]string
+}
+
+// EnqueueBatchEntry is one message within a SendMessageBatch request.
+type EnqueueBatchEntry struct {
+	Body         string
+	KeyValues    map[string]string
+	DelaySeconds int
+}
+
+// EnqueueFifoBatchEntry is one message within a SendMessageBatch request
+// against a FIFO queue; GroupId/DedupId carry the same MessageGroupId/
+// MessageDeduplicationId semantics as EnqueueFifo.
+type EnqueueFifoBatchEntry struct {
+	Body         string
+	KeyValues    map[string]string
+	DelaySeconds int
+	GroupId      string
+	DedupId      string
+}
+
+// EnqueueFifoBatchResult is what EnqueueFifoBatch returns for one entry,
+// mirroring EnqueueFifo's (messageId, sequenceNumber) pair.
+type EnqueueFifoBatchResult struct {
+	MessageId      int64
+	SequenceNumber string
+}
+
+// TenantManager resolves AWS-style credentials and the single-tenant
+// dashboard identity to a tenant ID.
+type TenantManager interface {
+	GetAWSSecretKey(accessKey, region string) (tenantId int64, secretKey string, err error)
+	GetTenant() int64
+}
+
+// Queue is the storage interface the SQS and SNS protocol servers are built
+// against. A concrete backend (MemoryQueue is the one in this package) stores
+// queues, messages, attributes, and in-flight leases per tenant.
+type Queue interface {
+	CreateQueue(tenantId int64, name string, attributes map[string]string) error
+	DeleteQueue(tenantId int64, name string) error
+	ListQueues(tenantId int64) ([]string, error)
+
+	Enqueue(tenantId int64, queue, body string, kv map[string]string, delaySeconds, visibilityTimeout int) (int64, error)
+	EnqueueBatch(tenantId int64, queue string, entries []EnqueueBatchEntry) ([]int64, error)
+	// EnqueueFifo enforces per-MessageGroupId ordering and the dedup window,
+	// returning the original message's ID and SequenceNumber when dedupId
+	// has already been seen within the window instead of enqueuing again.
+	EnqueueFifo(tenantId int64, queue, body string, kv map[string]string, delaySeconds, visibilityTimeout int, groupId, dedupId string) (messageId int64, sequenceNumber string, err error)
+	// EnqueueFifoBatch is EnqueueFifo's SendMessageBatch counterpart: every
+	// entry is deduped/inserted under a single critical section so a batch
+	// can't be interleaved with a concurrent send to the same FIFO queue.
+	EnqueueFifoBatch(tenantId int64, queue string, entries []EnqueueFifoBatchEntry) ([]EnqueueFifoBatchResult, error)
+
+	// Dequeue returns up to max visible messages, skipping FIFO groups with
+	// an in-flight message and redriving messages past their queue's
+	// maxReceiveCount to its dead-letter queue instead of returning them.
+	Dequeue(tenantId int64, queue string, max int) ([]Message, error)
+	Delete(tenantId int64, queue string, messageId int64) error
+	DeleteBatch(tenantId int64, queue string, messageIds []int64) error
+	// DeleteLeased deletes messageId only if receiveCount still matches its
+	// current lease generation, so a delayed delete from a prior lease can't
+	// remove a redelivered copy.
+	DeleteLeased(tenantId int64, queue string, messageId, receiveCount int64) error
+
+	// ChangeVisibilityLeased extends/shortens messageId's lease by seconds,
+	// subject to the same receiveCount generation check as DeleteLeased.
+	ChangeVisibilityLeased(tenantId int64, queue string, messageId, receiveCount int64, seconds int) error
+
+	Peek(tenantId int64, queue string, messageId int64) *Message
+	Filter(tenantId int64, queue string, criteria FilterCriteria) []int64
+	Stats(tenantId int64, queue string) QueueStats
+
+	GetAttributes(tenantId int64, queue string) map[string]string
+	SetAttributes(tenantId int64, queue string, attributes map[string]string) error
+
+	// Subscribe returns a channel that receives a value after the next
+	// Enqueue/EnqueueBatch/EnqueueFifo onto tenantId/queue, and a cancel
+	// func to release interest in it. The channel is shared by every
+	// waiter on a given tenant/queue, so fan-out is bounded regardless of
+	// how many concurrent long-polls are in flight.
+	Subscribe(tenantId int64, queue string) (notify <-chan struct{}, cancel func())
+}
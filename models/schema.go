@@ -0,0 +1,20 @@
+package models
+
+// Schema is one registered version of a subject's definition, scoped to a
+// tenant so two tenants can register unrelated schemas under the same name.
+type Schema struct {
+	Tenant     int64
+	Subject    string
+	Version    int
+	Codec      string
+	Definition string
+}
+
+// SchemaRegistry stores and retrieves schema versions keyed by
+// (tenant, subject, version). MemorySchemaRegistry is the implementation in
+// this package; it holds schemas in memory only.
+type SchemaRegistry interface {
+	Register(tenantId int64, subject, codec, definition string) (*Schema, error)
+	Get(tenantId int64, subject string, version int) (*Schema, error)
+	Latest(tenantId int64, subject string) (*Schema, error)
+}
@@ -22,9 +22,11 @@ type Dashboard struct {
 	app           *fiber.App
 	queue         models.Queue
 	tenantManager models.TenantManager
+	codecs        *models.CodecRegistry
+	schemas       models.SchemaRegistry
 }
 
-func NewDashboard(queue models.Queue, tenantManager models.TenantManager) *Dashboard {
+func NewDashboard(queue models.Queue, tenantManager models.TenantManager, codecs *models.CodecRegistry, schemas models.SchemaRegistry) *Dashboard {
 	http.FS(viewsfs)
 	fs2, err := fs.Sub(viewsfs, "views")
 	if err != nil {
@@ -44,6 +46,8 @@ func NewDashboard(queue models.Queue, tenantManager models.TenantManager) *Dashb
 		app:           app,
 		queue:         queue,
 		tenantManager: tenantManager,
+		codecs:        codecs,
+		schemas:       schemas,
 	}
 
 	app.Get("/", d.Queues)
@@ -119,17 +123,37 @@ func (d *Dashboard) Queue(c *fiber.Ctx) error {
 
 	}
 
+	// A separate "fields" filter mode matches against a message's decoded
+	// body (e.g. JSON/Avro fields) rather than its raw KV attributes.
+	fieldFilterString := c.Query("fields")
+	fieldFilter := make(map[string]string)
+	for _, field := range strings.Fields(fieldFilterString) {
+		if strings.Contains(field, "=") {
+			tokens := strings.SplitN(field, "=", 2)
+			fieldFilter[strings.TrimSpace(tokens[0])] = strings.TrimSpace(tokens[1])
+		}
+	}
+
 	filteredMessageIDs := d.queue.Filter(tenantId, queueName, filterCriteria)
 
 	messages := make([]*models.Message, 0)
 	for _, messageId := range filteredMessageIDs {
 		message := d.queue.Peek(tenantId, queueName, messageId)
-		if message != nil {
-			messages = append(messages, message)
+		if message == nil {
+			continue
+		}
+
+		if len(fieldFilter) > 0 {
+			decoded, ok := models.DecodeMessage(d.codecs, d.schemas, tenantId, message.KeyValues, message.Message)
+			if !ok || !matchesDecodedFields(decoded, fieldFilter) {
+				continue
+			}
 		}
+
+		messages = append(messages, message)
 	}
 
-	return c.Render("queue", fiber.Map{"Queue": queueName, "Stats": queueStats, "Messages": messages, "Filter": filterString}, "layout")
+	return c.Render("queue", fiber.Map{"Queue": queueName, "Stats": queueStats, "Messages": messages, "Filter": filterString, "FieldFilter": fieldFilterString}, "layout")
 }
 
 func (d *Dashboard) Message(c *fiber.Ctx) error {
@@ -148,14 +172,28 @@ func (d *Dashboard) Message(c *fiber.Ctx) error {
 		return errors.New("Message not found")
 	}
 
-	return c.Render("message", fiber.Map{"Queue": queueName, "Message": message}, "layout")
+	decoded, decodedOk := models.DecodeMessage(d.codecs, d.schemas, tenantId, message.KeyValues, message.Message)
+
+	return c.Render("message", fiber.Map{"Queue": queueName, "Message": message, "Decoded": decoded, "DecodedOk": decodedOk}, "layout")
+}
+
+// matchesDecodedFields reports whether every key=value pair in want is present
+// (as its string form) in a message's decoded body.
+func matchesDecodedFields(decoded map[string]any, want map[string]string) bool {
+	for key, value := range want {
+		got, ok := decoded[key]
+		if !ok || fmt.Sprintf("%v", got) != value {
+			return false
+		}
+	}
+	return true
 }
 
 func (d *Dashboard) NewQueue(c *fiber.Ctx) error {
 	queueName := c.FormValue("queue")
 
 	tenantId := d.tenantManager.GetTenant()
-	err := d.queue.CreateQueue(tenantId, queueName)
+	err := d.queue.CreateQueue(tenantId, queueName, nil)
 
 	if err != nil {
 		return err
@@ -0,0 +1,104 @@
+package sns
+
+type CreateTopicRequest struct {
+	Name       string            `json:"Name"`
+	Attributes map[string]string `json:"Attributes,omitempty"`
+	Tags       map[string]string `json:"Tags,omitempty"`
+}
+
+type CreateTopicResponse struct {
+	TopicArn string `json:"TopicArn"`
+}
+
+type ListTopicsRequest struct {
+	NextToken string `json:"NextToken,omitempty"`
+}
+
+type Topic struct {
+	TopicArn string `json:"TopicArn"`
+}
+
+type ListTopicsResponse struct {
+	Topics []Topic `json:"Topics"`
+}
+
+type SubscribeRequest struct {
+	TopicArn              string            `json:"TopicArn"`
+	Protocol              string            `json:"Protocol"`
+	Endpoint              string            `json:"Endpoint"`
+	Attributes            map[string]string `json:"Attributes,omitempty"`
+	ReturnSubscriptionArn bool              `json:"ReturnSubscriptionArn,omitempty"`
+}
+
+type SubscribeResponse struct {
+	SubscriptionArn string `json:"SubscriptionArn"`
+}
+
+type UnsubscribeRequest struct {
+	SubscriptionArn string `json:"SubscriptionArn"`
+}
+
+type UnsubscribeResponse struct{}
+
+type MessageAttributeValue struct {
+	StringValue string `json:"StringValue,omitempty"`
+	BinaryValue string `json:"BinaryValue,omitempty"`
+	DataType    string `json:"DataType"`
+}
+
+type PublishRequest struct {
+	TopicArn               string                           `json:"TopicArn"`
+	Message                string                           `json:"Message"`
+	Subject                string                           `json:"Subject,omitempty"`
+	MessageAttributes      map[string]MessageAttributeValue `json:"MessageAttributes,omitempty"`
+	MessageGroupId         string                           `json:"MessageGroupId,omitempty"`
+	MessageDeduplicationId string                           `json:"MessageDeduplicationId,omitempty"`
+}
+
+type PublishResponse struct {
+	MessageId string `json:"MessageId"`
+}
+
+type PublishBatchRequestEntry struct {
+	Id                     string                           `json:"Id"`
+	Message                string                           `json:"Message"`
+	Subject                string                           `json:"Subject,omitempty"`
+	MessageAttributes      map[string]MessageAttributeValue `json:"MessageAttributes,omitempty"`
+	MessageGroupId         string                           `json:"MessageGroupId,omitempty"`
+	MessageDeduplicationId string                           `json:"MessageDeduplicationId,omitempty"`
+}
+
+type PublishBatchRequest struct {
+	TopicArn                   string                     `json:"TopicArn"`
+	PublishBatchRequestEntries []PublishBatchRequestEntry `json:"PublishBatchRequestEntries"`
+}
+
+type PublishBatchResultEntry struct {
+	Id        string `json:"Id"`
+	MessageId string `json:"MessageId"`
+}
+
+type BatchResultErrorEntry struct {
+	Id          string `json:"Id"`
+	SenderFault bool   `json:"SenderFault"`
+	Code        string `json:"Code"`
+	Message     string `json:"Message,omitempty"`
+}
+
+type PublishBatchResponse struct {
+	Successful []PublishBatchResultEntry `json:"Successful"`
+	Failed     []BatchResultErrorEntry   `json:"Failed"`
+}
+
+// snsEnvelope is the standard SNS->SQS delivery wrapper: when a topic fans
+// out to a subscribed queue, the raw Message is wrapped in this JSON
+// envelope rather than delivered as-is (matching real SNS behavior).
+type snsEnvelope struct {
+	Type              string                           `json:"Type"`
+	MessageId         string                           `json:"MessageId"`
+	TopicArn          string                           `json:"TopicArn"`
+	Subject           string                           `json:"Subject,omitempty"`
+	Message           string                           `json:"Message"`
+	Timestamp         string                           `json:"Timestamp"`
+	MessageAttributes map[string]MessageAttributeValue `json:"MessageAttributes,omitempty"`
+}
@@ -0,0 +1,369 @@
+package sns
+
+/*
+Docs:
+https://docs.aws.amazon.com/sns/latest/api/API_Publish.html
+https://docs.aws.amazon.com/sns/latest/api/API_Subscribe.html
+
+Testing:
+aws sns create-topic --name a --endpoint-url http://localhost:3002
+aws sns subscribe --topic-arn arn:aws:sns:us-east-1:1:a --protocol sqs --notification-endpoint https://sqs.us-east-1.amazonaws.com/1/a --endpoint-url http://localhost:3002
+aws sns publish --topic-arn arn:aws:sns:us-east-1:1:a --message "hello world" --endpoint-url http://localhost:3002
+*/
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"q/models"
+	"strings"
+	"sync"
+	"time"
+
+	sqsproto "q/sqs/protocols/sqs"
+
+	"github.com/gofiber/contrib/fiberzerolog"
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/middleware/adaptor"
+	"github.com/rs/zerolog"
+	"github.com/valyala/fasthttp/fasthttpadaptor"
+)
+
+// subscription is a single Subscribe record: a topic fanning out to an
+// endpoint over a protocol (today, only "sqs" is delivered).
+type subscription struct {
+	arn      string
+	topicArn string
+	protocol string
+	endpoint string
+}
+
+type SNS struct {
+	app           *fiber.App
+	queue         models.Queue
+	tenantManager models.TenantManager
+
+	mu            sync.Mutex
+	topics        map[int64]map[string]bool          // tenantId -> topicArn -> exists
+	subscriptions map[int64]map[string]*subscription // tenantId -> subscriptionArn -> subscription
+}
+
+func NewSNS(queue models.Queue, tenantManager models.TenantManager) *SNS {
+	app := fiber.New(fiber.Config{DisableStartupMessage: true})
+
+	logger := zerolog.New(zerolog.ConsoleWriter{Out: os.Stderr}).With().Timestamp().Logger().Level(zerolog.ErrorLevel)
+
+	app.Use(fiberzerolog.New(fiberzerolog.Config{
+		Logger: &logger,
+	}))
+
+	s := &SNS{
+		app:           app,
+		queue:         queue,
+		tenantManager: tenantManager,
+		topics:        make(map[int64]map[string]bool),
+		subscriptions: make(map[int64]map[string]*subscription),
+	}
+
+	app.Use(s.authMiddleware)
+	app.Post("/*", s.Action)
+
+	return s
+}
+
+func (s *SNS) authMiddleware(c *fiber.Ctx) error {
+	r, err := adaptor.ConvertRequest(c, false)
+	if err != nil {
+		return err
+	}
+
+	awsHeader, err := sqsproto.ParseAuthorizationHeader(r)
+	if err != nil {
+		return err
+	}
+
+	tenantId, secretKey, err := s.tenantManager.GetAWSSecretKey(awsHeader.AccessKey, awsHeader.Region)
+	if err != nil {
+		return err
+	}
+
+	err = sqsproto.ValidateAWSRequest(awsHeader, secretKey, r)
+	if err != nil {
+		return err
+	}
+
+	c.Locals("tenantId", tenantId)
+	return c.Next()
+}
+
+func (s *SNS) Start() error {
+	fmt.Println("SNS Endpoint: http://localhost:3002")
+	return s.app.Listen(":3002")
+}
+
+func (s *SNS) Stop() error {
+	return s.app.Shutdown()
+}
+
+func (s *SNS) Action(c *fiber.Ctx) error {
+	awsMethodHeader, ok := c.GetReqHeaders()["X-Amz-Target"]
+	if !ok {
+		return errors.New("X-Amz-Target header not found")
+	}
+	awsMethod := awsMethodHeader[0]
+
+	var r *http.Request = &http.Request{}
+	fasthttpadaptor.ConvertRequest(c.Context(), r, false)
+
+	tenantId := c.Locals("tenantId").(int64)
+
+	switch awsMethod {
+	case "AmazonSNS.CreateTopic":
+		return s.CreateTopic(c, tenantId)
+	case "AmazonSNS.ListTopics":
+		return s.ListTopics(c, tenantId)
+	case "AmazonSNS.Subscribe":
+		return s.Subscribe(c, tenantId)
+	case "AmazonSNS.Unsubscribe":
+		return s.Unsubscribe(c, tenantId)
+	case "AmazonSNS.Publish":
+		return s.Publish(c, tenantId)
+	case "AmazonSNS.PublishBatch":
+		return s.PublishBatch(c, tenantId)
+	default:
+		return fmt.Errorf("SNS method %s not implemented", awsMethod)
+	}
+}
+
+func topicArn(tenantId int64, name string) string {
+	return fmt.Sprintf("arn:aws:sns:us-east-1:%d:%s", tenantId, name)
+}
+
+func randomId(numBytes int) string {
+	buf := make([]byte, numBytes)
+	_, err := rand.Read(buf)
+	if err != nil {
+		log.Println(err)
+	}
+	return hex.EncodeToString(buf)
+}
+
+func (s *SNS) CreateTopic(c *fiber.Ctx, tenantId int64) error {
+	req := &CreateTopicRequest{}
+
+	err := json.Unmarshal(c.Body(), req)
+	if err != nil {
+		return err
+	}
+
+	arn := topicArn(tenantId, req.Name)
+
+	s.mu.Lock()
+	if s.topics[tenantId] == nil {
+		s.topics[tenantId] = make(map[string]bool)
+	}
+	s.topics[tenantId][arn] = true
+	s.mu.Unlock()
+
+	return c.JSON(CreateTopicResponse{TopicArn: arn})
+}
+
+func (s *SNS) ListTopics(c *fiber.Ctx, tenantId int64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	topics := make([]Topic, 0, len(s.topics[tenantId]))
+	for arn := range s.topics[tenantId] {
+		topics = append(topics, Topic{TopicArn: arn})
+	}
+
+	return c.JSON(ListTopicsResponse{Topics: topics})
+}
+
+func (s *SNS) Subscribe(c *fiber.Ctx, tenantId int64) error {
+	req := &SubscribeRequest{}
+
+	err := json.Unmarshal(c.Body(), req)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if !s.topics[tenantId][req.TopicArn] {
+		return fmt.Errorf("topic %s not found", req.TopicArn)
+	}
+
+	arn := fmt.Sprintf("%s:%s", req.TopicArn, randomId(16))
+	if s.subscriptions[tenantId] == nil {
+		s.subscriptions[tenantId] = make(map[string]*subscription)
+	}
+	s.subscriptions[tenantId][arn] = &subscription{
+		arn:      arn,
+		topicArn: req.TopicArn,
+		protocol: req.Protocol,
+		endpoint: req.Endpoint,
+	}
+
+	return c.JSON(SubscribeResponse{SubscriptionArn: arn})
+}
+
+func (s *SNS) Unsubscribe(c *fiber.Ctx, tenantId int64) error {
+	req := &UnsubscribeRequest{}
+
+	err := json.Unmarshal(c.Body(), req)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	delete(s.subscriptions[tenantId], req.SubscriptionArn)
+	s.mu.Unlock()
+
+	return c.JSON(UnsubscribeResponse{})
+}
+
+// subscribersFor returns the sqs subscriptions currently fanning out from
+// topicArn, snapshotted under the lock so Publish can deliver without
+// holding it.
+func (s *SNS) subscribersFor(tenantId int64, topicArn string) []*subscription {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	subs := make([]*subscription, 0)
+	for _, sub := range s.subscriptions[tenantId] {
+		if sub.topicArn == topicArn && sub.protocol == "sqs" {
+			subs = append(subs, sub)
+		}
+	}
+	return subs
+}
+
+// isFifoQueueName reports whether queue follows the FIFO queue naming
+// convention (a ".fifo" suffix, as required by SQS).
+func isFifoQueueName(queue string) bool {
+	return strings.HasSuffix(queue, ".fifo")
+}
+
+// isFifoQueue reports whether queue is a FIFO queue: either its name ends
+// in ".fifo", or it was created/configured with the FifoQueue attribute set
+// to "true". Either is sufficient to turn on FIFO semantics.
+func (s *SNS) isFifoQueue(tenantId int64, queue string) bool {
+	if isFifoQueueName(queue) {
+		return true
+	}
+	return s.queue.GetAttributes(tenantId, queue)["FifoQueue"] == "true"
+}
+
+// deliver enqueues the SNS-wrapped envelope onto every queue subscribed to
+// topicArn, returning the SNS MessageId assigned to this publish. It
+// returns an error without delivering anything if topicArn hasn't been
+// created.
+func (s *SNS) deliver(tenantId int64, topicArn, subject, message, groupId, dedupId string, attrs map[string]MessageAttributeValue) (string, error) {
+	s.mu.Lock()
+	exists := s.topics[tenantId][topicArn]
+	s.mu.Unlock()
+	if !exists {
+		return "", fmt.Errorf("topic %s not found", topicArn)
+	}
+
+	messageId := randomId(16)
+
+	// An empty dedupId would otherwise collide with every other
+	// undeduplicated publish to a FIFO subscriber; give each one its own.
+	effectiveDedupId := dedupId
+	if effectiveDedupId == "" {
+		effectiveDedupId = messageId
+	}
+
+	envelope := snsEnvelope{
+		Type:              "Notification",
+		MessageId:         messageId,
+		TopicArn:          topicArn,
+		Subject:           subject,
+		Message:           message,
+		Timestamp:         time.Now().UTC().Format(time.RFC3339),
+		MessageAttributes: attrs,
+	}
+
+	body, err := json.Marshal(envelope)
+	if err != nil {
+		return "", err
+	}
+
+	for _, sub := range s.subscribersFor(tenantId, topicArn) {
+		tokens := strings.Split(sub.endpoint, "/")
+		queue := tokens[len(tokens)-1]
+
+		if s.isFifoQueue(tenantId, queue) {
+			if _, _, err := s.queue.EnqueueFifo(tenantId, queue, string(body), nil, 0, 30, groupId, effectiveDedupId); err != nil {
+				return "", err
+			}
+			continue
+		}
+
+		if _, err := s.queue.Enqueue(tenantId, queue, string(body), nil, 0, 30); err != nil {
+			return "", err
+		}
+	}
+
+	return messageId, nil
+}
+
+func (s *SNS) Publish(c *fiber.Ctx, tenantId int64) error {
+	req := &PublishRequest{}
+
+	err := json.Unmarshal(c.Body(), req)
+	if err != nil {
+		return err
+	}
+
+	messageId, err := s.deliver(tenantId, req.TopicArn, req.Subject, req.Message, req.MessageGroupId, req.MessageDeduplicationId, req.MessageAttributes)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(PublishResponse{MessageId: messageId})
+}
+
+// PublishBatch accepts up to 10 entries; a failure delivering one entry is
+// reported in Failed and does not abort the rest of the batch.
+func (s *SNS) PublishBatch(c *fiber.Ctx, tenantId int64) error {
+	req := &PublishBatchRequest{}
+
+	err := json.Unmarshal(c.Body(), req)
+	if err != nil {
+		return err
+	}
+
+	if len(req.PublishBatchRequestEntries) > 10 {
+		return fmt.Errorf("publish batch request cannot contain more than 10 entries")
+	}
+
+	resp := PublishBatchResponse{}
+	seenIds := make(map[string]bool, len(req.PublishBatchRequestEntries))
+
+	for _, entry := range req.PublishBatchRequestEntries {
+		if seenIds[entry.Id] {
+			resp.Failed = append(resp.Failed, BatchResultErrorEntry{Id: entry.Id, SenderFault: true, Code: "BatchEntryIdsNotDistinct", Message: "Id repeated in batch"})
+			continue
+		}
+		seenIds[entry.Id] = true
+
+		messageId, err := s.deliver(tenantId, req.TopicArn, entry.Subject, entry.Message, entry.MessageGroupId, entry.MessageDeduplicationId, entry.MessageAttributes)
+		if err != nil {
+			resp.Failed = append(resp.Failed, BatchResultErrorEntry{Id: entry.Id, SenderFault: false, Code: "InternalError", Message: err.Error()})
+			continue
+		}
+
+		resp.Successful = append(resp.Successful, PublishBatchResultEntry{Id: entry.Id, MessageId: messageId})
+	}
+
+	return c.JSON(resp)
+}
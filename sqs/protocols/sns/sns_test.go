@@ -0,0 +1,67 @@
+package sns
+
+import (
+	"encoding/json"
+	"testing"
+
+	"q/models"
+)
+
+func newTestSNS() *SNS {
+	return &SNS{
+		queue:         models.NewMemoryQueue(),
+		topics:        make(map[int64]map[string]bool),
+		subscriptions: make(map[int64]map[string]*subscription),
+	}
+}
+
+func TestDeliverRejectsUnknownTopic(t *testing.T) {
+	s := newTestSNS()
+
+	if _, err := s.deliver(1, topicArn(1, "missing"), "", "hello", "", "", nil); err == nil {
+		t.Fatal("expected deliver to reject a topic that was never created")
+	}
+}
+
+func TestDeliverForwardsFifoGroupAndDedup(t *testing.T) {
+	s := newTestSNS()
+	arn := topicArn(1, "orders")
+	s.topics[1] = map[string]bool{arn: true}
+
+	if err := s.queue.CreateQueue(1, "subscriber.fifo", nil); err != nil {
+		t.Fatalf("CreateQueue: %v", err)
+	}
+	s.subscriptions[1] = map[string]*subscription{
+		"sub-1": {arn: "sub-1", topicArn: arn, protocol: "sqs", endpoint: "https://sqs.us-east-1.amazonaws.com/1/subscriber.fifo"},
+	}
+
+	if _, err := s.deliver(1, arn, "", "hello", "group-a", "dedup-1", nil); err != nil {
+		t.Fatalf("deliver: %v", err)
+	}
+	// A second publish with the same dedup id must not enqueue another copy.
+	if _, err := s.deliver(1, arn, "", "hello", "group-a", "dedup-1", nil); err != nil {
+		t.Fatalf("deliver: %v", err)
+	}
+	if total := s.queue.Stats(1, "subscriber.fifo").TotalMessages; total != 1 {
+		t.Fatalf("expected the repeated dedup id to be deduplicated, got %d messages", total)
+	}
+
+	messages, err := s.queue.Dequeue(1, "subscriber.fifo", 10)
+	if err != nil {
+		t.Fatalf("Dequeue: %v", err)
+	}
+	if len(messages) != 1 {
+		t.Fatalf("expected 1 delivered message, got %d", len(messages))
+	}
+	if messages[0].GroupId != "group-a" {
+		t.Fatalf("expected delivered message to keep its MessageGroupId, got %q", messages[0].GroupId)
+	}
+
+	var envelope snsEnvelope
+	if err := json.Unmarshal(messages[0].Message, &envelope); err != nil {
+		t.Fatalf("unmarshal envelope: %v", err)
+	}
+	if envelope.Message != "hello" {
+		t.Fatalf("expected envelope to wrap %q, got %q", "hello", envelope.Message)
+	}
+}
@@ -0,0 +1,303 @@
+package sqs
+
+import (
+	"crypto/md5"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"q/models"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// SQS enforces these limits on every *Batch action.
+const (
+	maxBatchEntries      = 10
+	maxBatchPayloadBytes = 256 * 1024
+)
+
+type SendMessageBatchRequestEntry struct {
+	Id                     string                           `json:"Id"`
+	MessageBody            string                           `json:"MessageBody"`
+	DelaySeconds           int                              `json:"DelaySeconds,omitempty"`
+	MessageAttributes      map[string]MessageAttributeValue `json:"MessageAttributes,omitempty"`
+	MessageDeduplicationId string                           `json:"MessageDeduplicationId,omitempty"`
+	MessageGroupId         string                           `json:"MessageGroupId,omitempty"`
+}
+
+type SendMessageBatchRequest struct {
+	QueueUrl string                         `json:"QueueUrl"`
+	Entries  []SendMessageBatchRequestEntry `json:"Entries"`
+}
+
+type SendMessageBatchResultEntry struct {
+	Id                     string `json:"Id"`
+	MessageId              string `json:"MessageId"`
+	MD5OfMessageBody       string `json:"MD5OfMessageBody"`
+	MD5OfMessageAttributes string `json:"MD5OfMessageAttributes,omitempty"`
+	SequenceNumber         string `json:"SequenceNumber,omitempty"`
+}
+
+type BatchResultErrorEntry struct {
+	Id          string `json:"Id"`
+	SenderFault bool   `json:"SenderFault"`
+	Code        string `json:"Code"`
+	Message     string `json:"Message,omitempty"`
+}
+
+type SendMessageBatchResponse struct {
+	Successful []SendMessageBatchResultEntry `json:"Successful"`
+	Failed     []BatchResultErrorEntry       `json:"Failed"`
+}
+
+type DeleteMessageBatchRequestEntry struct {
+	Id            string `json:"Id"`
+	ReceiptHandle string `json:"ReceiptHandle"`
+}
+
+type DeleteMessageBatchRequest struct {
+	QueueUrl string                           `json:"QueueUrl"`
+	Entries  []DeleteMessageBatchRequestEntry `json:"Entries"`
+}
+
+type DeleteMessageBatchResultEntry struct {
+	Id string `json:"Id"`
+}
+
+type DeleteMessageBatchResponse struct {
+	Successful []DeleteMessageBatchResultEntry `json:"Successful"`
+	Failed     []BatchResultErrorEntry         `json:"Failed"`
+}
+
+type ChangeMessageVisibilityBatchRequestEntry struct {
+	Id                string `json:"Id"`
+	ReceiptHandle     string `json:"ReceiptHandle"`
+	VisibilityTimeout int    `json:"VisibilityTimeout"`
+}
+
+type ChangeMessageVisibilityBatchRequest struct {
+	QueueUrl string                                     `json:"QueueUrl"`
+	Entries  []ChangeMessageVisibilityBatchRequestEntry `json:"Entries"`
+}
+
+type ChangeMessageVisibilityBatchResultEntry struct {
+	Id string `json:"Id"`
+}
+
+type ChangeMessageVisibilityBatchResponse struct {
+	Successful []ChangeMessageVisibilityBatchResultEntry `json:"Successful"`
+	Failed     []BatchResultErrorEntry                   `json:"Failed"`
+}
+
+func (s *SQS) SendMessageBatch(c *fiber.Ctx, tenantId int64) error {
+	req := &SendMessageBatchRequest{}
+
+	err := json.Unmarshal(c.Body(), req)
+	if err != nil {
+		return err
+	}
+
+	if len(req.Entries) > maxBatchEntries {
+		return fmt.Errorf("batch request cannot contain more than %d entries", maxBatchEntries)
+	}
+
+	tokens := strings.Split(req.QueueUrl, "/")
+	queue := tokens[len(tokens)-1]
+
+	resp := SendMessageBatchResponse{}
+
+	seenIds := make(map[string]bool, len(req.Entries))
+	accepted := make([]SendMessageBatchRequestEntry, 0, len(req.Entries))
+	kvs := make([]map[string]string, 0, len(req.Entries))
+	totalSize := 0
+
+	for _, entry := range req.Entries {
+		if seenIds[entry.Id] {
+			resp.Failed = append(resp.Failed, BatchResultErrorEntry{Id: entry.Id, SenderFault: true, Code: "BatchEntryIdsNotDistinct", Message: "Id repeated in batch"})
+			continue
+		}
+		seenIds[entry.Id] = true
+
+		totalSize += len(entry.MessageBody)
+		if totalSize > maxBatchPayloadBytes {
+			resp.Failed = append(resp.Failed, BatchResultErrorEntry{Id: entry.Id, SenderFault: true, Code: "BatchRequestTooLong", Message: "Batch requests cannot be longer than 256KiB"})
+			continue
+		}
+
+		kv := make(map[string]string)
+		for k, v := range entry.MessageAttributes {
+			kv[k+"_DataType"] = v.DataType
+			if v.DataType == "String" || v.DataType == "Number" {
+				kv[k] = v.StringValue
+			} else if v.DataType == "Binary" {
+				kv[k] = v.BinaryValue
+			}
+		}
+
+		accepted = append(accepted, entry)
+		kvs = append(kvs, kv)
+	}
+
+	if len(accepted) == 0 {
+		return c.JSON(resp)
+	}
+
+	if s.isFifoQueue(tenantId, queue) {
+		contentBasedDedup := s.queue.GetAttributes(tenantId, queue)["ContentBasedDeduplication"] == "true"
+
+		fifoEntries := make([]models.EnqueueFifoBatchEntry, len(accepted))
+		for i, entry := range accepted {
+			dedupId := entry.MessageDeduplicationId
+			if dedupId == "" {
+				if !contentBasedDedup {
+					return errors.New("MissingParameter: The request must contain a non-empty value for MessageDeduplicationId, or the queue must have ContentBasedDeduplication enabled")
+				}
+				contentHash := sha256.Sum256([]byte(entry.MessageBody))
+				dedupId = hex.EncodeToString(contentHash[:])
+			}
+
+			fifoEntries[i] = models.EnqueueFifoBatchEntry{
+				Body:         entry.MessageBody,
+				KeyValues:    kvs[i],
+				DelaySeconds: entry.DelaySeconds,
+				GroupId:      entry.MessageGroupId,
+				DedupId:      dedupId,
+			}
+		}
+
+		results, err := s.queue.EnqueueFifoBatch(tenantId, queue, fifoEntries)
+		if err != nil {
+			return err
+		}
+
+		for i, entry := range accepted {
+			hasher := md5.New()
+			hasher.Write([]byte(entry.MessageBody))
+
+			resp.Successful = append(resp.Successful, SendMessageBatchResultEntry{
+				Id:               entry.Id,
+				MessageId:        fmt.Sprintf("%d", results[i].MessageId),
+				MD5OfMessageBody: hex.EncodeToString(hasher.Sum(nil)),
+				SequenceNumber:   results[i].SequenceNumber,
+			})
+		}
+
+		return c.JSON(resp)
+	}
+
+	entries := make([]models.EnqueueBatchEntry, len(accepted))
+	for i, entry := range accepted {
+		entries[i] = models.EnqueueBatchEntry{
+			Body:         entry.MessageBody,
+			KeyValues:    kvs[i],
+			DelaySeconds: entry.DelaySeconds,
+		}
+	}
+
+	messageIds, err := s.queue.EnqueueBatch(tenantId, queue, entries)
+	if err != nil {
+		return err
+	}
+
+	for i, entry := range accepted {
+		hasher := md5.New()
+		hasher.Write([]byte(entry.MessageBody))
+
+		resp.Successful = append(resp.Successful, SendMessageBatchResultEntry{
+			Id:               entry.Id,
+			MessageId:        fmt.Sprintf("%d", messageIds[i]),
+			MD5OfMessageBody: hex.EncodeToString(hasher.Sum(nil)),
+		})
+	}
+
+	return c.JSON(resp)
+}
+
+func (s *SQS) DeleteMessageBatch(c *fiber.Ctx, tenantId int64) error {
+	req := &DeleteMessageBatchRequest{}
+
+	err := json.Unmarshal(c.Body(), req)
+	if err != nil {
+		return err
+	}
+
+	if len(req.Entries) > maxBatchEntries {
+		return fmt.Errorf("batch request cannot contain more than %d entries", maxBatchEntries)
+	}
+
+	tokens := strings.Split(req.QueueUrl, "/")
+	queue := tokens[len(tokens)-1]
+
+	resp := DeleteMessageBatchResponse{}
+
+	seenIds := make(map[string]bool, len(req.Entries))
+
+	for _, entry := range req.Entries {
+		if seenIds[entry.Id] {
+			resp.Failed = append(resp.Failed, BatchResultErrorEntry{Id: entry.Id, SenderFault: true, Code: "BatchEntryIdsNotDistinct", Message: "Id repeated in batch"})
+			continue
+		}
+		seenIds[entry.Id] = true
+
+		rh, err := s.verifyReceiptHandle(entry.ReceiptHandle, tenantId, queue)
+		if err != nil {
+			resp.Failed = append(resp.Failed, BatchResultErrorEntry{Id: entry.Id, SenderFault: true, Code: "ReceiptHandleIsInvalid", Message: err.Error()})
+			continue
+		}
+
+		if err := s.queue.DeleteLeased(tenantId, queue, rh.MessageId, rh.ReceiveCount); err != nil {
+			resp.Failed = append(resp.Failed, BatchResultErrorEntry{Id: entry.Id, SenderFault: true, Code: "ReceiptHandleIsInvalid", Message: err.Error()})
+			continue
+		}
+
+		resp.Successful = append(resp.Successful, DeleteMessageBatchResultEntry{Id: entry.Id})
+	}
+
+	return c.JSON(resp)
+}
+
+func (s *SQS) ChangeMessageVisibilityBatch(c *fiber.Ctx, tenantId int64) error {
+	req := &ChangeMessageVisibilityBatchRequest{}
+
+	err := json.Unmarshal(c.Body(), req)
+	if err != nil {
+		return err
+	}
+
+	if len(req.Entries) > maxBatchEntries {
+		return fmt.Errorf("batch request cannot contain more than %d entries", maxBatchEntries)
+	}
+
+	tokens := strings.Split(req.QueueUrl, "/")
+	queue := tokens[len(tokens)-1]
+
+	resp := ChangeMessageVisibilityBatchResponse{}
+
+	seenIds := make(map[string]bool, len(req.Entries))
+
+	for _, entry := range req.Entries {
+		if seenIds[entry.Id] {
+			resp.Failed = append(resp.Failed, BatchResultErrorEntry{Id: entry.Id, SenderFault: true, Code: "BatchEntryIdsNotDistinct", Message: "Id repeated in batch"})
+			continue
+		}
+		seenIds[entry.Id] = true
+
+		rh, err := s.verifyReceiptHandle(entry.ReceiptHandle, tenantId, queue)
+		if err != nil {
+			resp.Failed = append(resp.Failed, BatchResultErrorEntry{Id: entry.Id, SenderFault: true, Code: "ReceiptHandleIsInvalid", Message: err.Error()})
+			continue
+		}
+
+		if err := s.queue.ChangeVisibilityLeased(tenantId, queue, rh.MessageId, rh.ReceiveCount, entry.VisibilityTimeout); err != nil {
+			resp.Failed = append(resp.Failed, BatchResultErrorEntry{Id: entry.Id, SenderFault: true, Code: "ReceiptHandleIsInvalid", Message: err.Error()})
+			continue
+		}
+
+		resp.Successful = append(resp.Successful, ChangeMessageVisibilityBatchResultEntry{Id: entry.Id})
+	}
+
+	return c.JSON(resp)
+}
@@ -0,0 +1,194 @@
+package sqs
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"q/models"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// newTestApp wires a bare fiber app directly to one SQS handler, bypassing
+// the AWS auth middleware NewSQS installs, so tests can drive the batch
+// entrypoints' own validation logic in isolation.
+func newTestApp(t *testing.T, queue models.Queue, handler func(s *SQS) func(c *fiber.Ctx, tenantId int64) error) (*fiber.App, *SQS) {
+	t.Helper()
+
+	s := &SQS{queue: queue}
+	app := fiber.New(fiber.Config{DisableStartupMessage: true})
+	app.Post("/", func(c *fiber.Ctx) error {
+		return handler(s)(c, 1)
+	})
+	return app, s
+}
+
+func doPost(t *testing.T, app *fiber.App, body any) []byte {
+	t.Helper()
+
+	raw, err := json.Marshal(body)
+	if err != nil {
+		t.Fatalf("marshal request: %v", err)
+	}
+
+	req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(string(raw)))
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := app.Test(req)
+	if err != nil {
+		t.Fatalf("app.Test: %v", err)
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatalf("read response: %v", err)
+	}
+	return respBody
+}
+
+func TestSendMessageBatchRejectsDuplicateIds(t *testing.T) {
+	queue := models.NewMemoryQueue()
+	if err := queue.CreateQueue(1, "orders", nil); err != nil {
+		t.Fatalf("CreateQueue: %v", err)
+	}
+
+	app, _ := newTestApp(t, queue, func(s *SQS) func(c *fiber.Ctx, tenantId int64) error {
+		return s.SendMessageBatch
+	})
+
+	respBody := doPost(t, app, SendMessageBatchRequest{
+		QueueUrl: "https://sqs.us-east-1.amazonaws.com/1/orders",
+		Entries: []SendMessageBatchRequestEntry{
+			{Id: "a", MessageBody: "one"},
+			{Id: "a", MessageBody: "two"},
+		},
+	})
+
+	var resp SendMessageBatchResponse
+	if err := json.Unmarshal(respBody, &resp); err != nil {
+		t.Fatalf("unmarshal response: %v, body=%s", err, respBody)
+	}
+
+	if len(resp.Successful) != 1 {
+		t.Fatalf("expected 1 successful entry, got %d", len(resp.Successful))
+	}
+	if len(resp.Failed) != 1 || resp.Failed[0].Code != "BatchEntryIdsNotDistinct" {
+		t.Fatalf("expected 1 failed entry with BatchEntryIdsNotDistinct, got %+v", resp.Failed)
+	}
+}
+
+func TestSendMessageBatchRejectsOversizedBatch(t *testing.T) {
+	queue := models.NewMemoryQueue()
+	if err := queue.CreateQueue(1, "orders", nil); err != nil {
+		t.Fatalf("CreateQueue: %v", err)
+	}
+
+	app, _ := newTestApp(t, queue, func(s *SQS) func(c *fiber.Ctx, tenantId int64) error {
+		return s.SendMessageBatch
+	})
+
+	entries := make([]SendMessageBatchRequestEntry, maxBatchEntries+1)
+	for i := range entries {
+		entries[i] = SendMessageBatchRequestEntry{Id: string(rune('a' + i)), MessageBody: "x"}
+	}
+
+	req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(mustJSON(t, SendMessageBatchRequest{
+		QueueUrl: "https://sqs.us-east-1.amazonaws.com/1/orders",
+		Entries:  entries,
+	})))
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := app.Test(req)
+	if err != nil {
+		t.Fatalf("app.Test: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusOK {
+		t.Fatalf("expected an error response for a batch over %d entries", maxBatchEntries)
+	}
+}
+
+func TestSendMessageBatchRoutesFifoQueueThroughEnqueueFifoBatch(t *testing.T) {
+	queue := models.NewMemoryQueue()
+	if err := queue.CreateQueue(1, "orders.fifo", nil); err != nil {
+		t.Fatalf("CreateQueue: %v", err)
+	}
+
+	app, _ := newTestApp(t, queue, func(s *SQS) func(c *fiber.Ctx, tenantId int64) error {
+		return s.SendMessageBatch
+	})
+
+	respBody := doPost(t, app, SendMessageBatchRequest{
+		QueueUrl: "https://sqs.us-east-1.amazonaws.com/1/orders.fifo",
+		Entries: []SendMessageBatchRequestEntry{
+			{Id: "a", MessageBody: "first", MessageGroupId: "group-a", MessageDeduplicationId: "dedup-1"},
+			{Id: "b", MessageBody: "second", MessageGroupId: "group-a", MessageDeduplicationId: "dedup-2"},
+		},
+	})
+
+	var resp SendMessageBatchResponse
+	if err := json.Unmarshal(respBody, &resp); err != nil {
+		t.Fatalf("unmarshal response: %v, body=%s", err, respBody)
+	}
+	if len(resp.Successful) != 2 {
+		t.Fatalf("expected 2 successful entries, got %+v", resp)
+	}
+	for _, entry := range resp.Successful {
+		if entry.SequenceNumber == "" {
+			t.Fatalf("expected a SequenceNumber for a FIFO batch entry, got %+v", entry)
+		}
+	}
+
+	// Only the first message of group-a should be visible while it's leased.
+	messages, err := queue.Dequeue(1, "orders.fifo", 10)
+	if err != nil {
+		t.Fatalf("Dequeue: %v", err)
+	}
+	if len(messages) != 1 || string(messages[0].Message) != "first" {
+		t.Fatalf("expected FIFO group ordering to hold for a batch send, got %+v", messages)
+	}
+}
+
+func TestSendMessageBatchRejectsFifoEntryWithoutDedupId(t *testing.T) {
+	queue := models.NewMemoryQueue()
+	if err := queue.CreateQueue(1, "orders.fifo", nil); err != nil {
+		t.Fatalf("CreateQueue: %v", err)
+	}
+
+	app, _ := newTestApp(t, queue, func(s *SQS) func(c *fiber.Ctx, tenantId int64) error {
+		return s.SendMessageBatch
+	})
+
+	req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(mustJSON(t, SendMessageBatchRequest{
+		QueueUrl: "https://sqs.us-east-1.amazonaws.com/1/orders.fifo",
+		Entries: []SendMessageBatchRequestEntry{
+			{Id: "a", MessageBody: "first", MessageGroupId: "group-a"},
+		},
+	})))
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := app.Test(req)
+	if err != nil {
+		t.Fatalf("app.Test: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusOK {
+		t.Fatalf("expected an error response for a FIFO batch entry missing MessageDeduplicationId without ContentBasedDeduplication")
+	}
+}
+
+func mustJSON(t *testing.T, v any) string {
+	t.Helper()
+	raw, err := json.Marshal(v)
+	if err != nil {
+		t.Fatalf("marshal: %v", err)
+	}
+	return string(raw)
+}
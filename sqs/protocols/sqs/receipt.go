@@ -0,0 +1,120 @@
+package sqs
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"time"
+)
+
+var errReceiptHandleInvalid = errors.New("ReceiptHandleIsInvalid")
+
+// receiptHandleHeaderSize is the fixed-width portion of an encoded
+// receiptHandle: tenantId, messageId, receiveCount, leaseExpiry, nonce,
+// each a big-endian uint64. The queue name follows as a variable-length tail.
+const receiptHandleHeaderSize = 8 * 5
+
+// receiptHandle is the decoded form of an opaque ReceiptHandle. It carries
+// everything needed to act on a lease without exposing the raw message ID,
+// and to detect a delete/visibility-change racing against redelivery.
+type receiptHandle struct {
+	TenantId     int64
+	Queue        string
+	MessageId    int64
+	ReceiveCount int64
+	LeaseExpiry  int64
+	Nonce        uint64
+}
+
+// issueReceiptHandle signs a fresh lease for a message just handed out by
+// ReceiveMessage.
+func (s *SQS) issueReceiptHandle(tenantId int64, queue string, messageId, receiveCount int64, leaseSeconds int) string {
+	nonceBuf := make([]byte, 8)
+	rand.Read(nonceBuf)
+
+	rh := receiptHandle{
+		TenantId:     tenantId,
+		Queue:        queue,
+		MessageId:    messageId,
+		ReceiveCount: receiveCount,
+		LeaseExpiry:  time.Now().Add(time.Duration(leaseSeconds) * time.Second).Unix(),
+		Nonce:        binary.BigEndian.Uint64(nonceBuf),
+	}
+
+	payload := encodeReceiptHandle(rh)
+	signed := append(payload, hmacSum(s.receiptSecret, payload)...)
+	return base64.RawURLEncoding.EncodeToString(signed)
+}
+
+// verifyReceiptHandle decodes and HMAC-verifies handle, accepting the
+// previous secret during a rotation grace period, and rejects it if it has
+// expired, was issued for a different queue, or belongs to a different
+// tenant than tenantId — the queue name alone isn't tenant-scoped, so two
+// tenants can share one, and the tenant check keeps a handle from one from
+// being replayed against the other's queue.
+func (s *SQS) verifyReceiptHandle(handle string, tenantId int64, queue string) (receiptHandle, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(handle)
+	if err != nil || len(raw) < receiptHandleHeaderSize+sha256.Size {
+		return receiptHandle{}, errReceiptHandleInvalid
+	}
+
+	payload := raw[:len(raw)-sha256.Size]
+	signature := raw[len(raw)-sha256.Size:]
+
+	valid := hmac.Equal(signature, hmacSum(s.receiptSecret, payload))
+	if !valid && len(s.previousReceiptSecret) > 0 {
+		valid = hmac.Equal(signature, hmacSum(s.previousReceiptSecret, payload))
+	}
+	if !valid {
+		return receiptHandle{}, errReceiptHandleInvalid
+	}
+
+	rh, err := decodeReceiptHandle(payload)
+	if err != nil {
+		return receiptHandle{}, err
+	}
+
+	if rh.Queue != queue || rh.TenantId != tenantId {
+		return receiptHandle{}, errReceiptHandleInvalid
+	}
+
+	if time.Now().Unix() > rh.LeaseExpiry {
+		return receiptHandle{}, errReceiptHandleInvalid
+	}
+
+	return rh, nil
+}
+
+func hmacSum(secret, payload []byte) []byte {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write(payload)
+	return mac.Sum(nil)
+}
+
+func encodeReceiptHandle(rh receiptHandle) []byte {
+	buf := make([]byte, receiptHandleHeaderSize, receiptHandleHeaderSize+len(rh.Queue))
+	binary.BigEndian.PutUint64(buf[0:8], uint64(rh.TenantId))
+	binary.BigEndian.PutUint64(buf[8:16], uint64(rh.MessageId))
+	binary.BigEndian.PutUint64(buf[16:24], uint64(rh.ReceiveCount))
+	binary.BigEndian.PutUint64(buf[24:32], uint64(rh.LeaseExpiry))
+	binary.BigEndian.PutUint64(buf[32:40], rh.Nonce)
+	return append(buf, []byte(rh.Queue)...)
+}
+
+func decodeReceiptHandle(payload []byte) (receiptHandle, error) {
+	if len(payload) < receiptHandleHeaderSize {
+		return receiptHandle{}, errReceiptHandleInvalid
+	}
+
+	return receiptHandle{
+		TenantId:     int64(binary.BigEndian.Uint64(payload[0:8])),
+		MessageId:    int64(binary.BigEndian.Uint64(payload[8:16])),
+		ReceiveCount: int64(binary.BigEndian.Uint64(payload[16:24])),
+		LeaseExpiry:  int64(binary.BigEndian.Uint64(payload[24:32])),
+		Nonce:        binary.BigEndian.Uint64(payload[32:40]),
+		Queue:        string(payload[receiptHandleHeaderSize:]),
+	}, nil
+}
@@ -0,0 +1,88 @@
+package sqs
+
+import "testing"
+
+func TestReceiptHandleRoundTrip(t *testing.T) {
+	s := &SQS{receiptSecret: []byte("secret")}
+
+	handle := s.issueReceiptHandle(1, "orders", 42, 1, 30)
+
+	rh, err := s.verifyReceiptHandle(handle, 1, "orders")
+	if err != nil {
+		t.Fatalf("verifyReceiptHandle: %v", err)
+	}
+	if rh.MessageId != 42 || rh.ReceiveCount != 1 || rh.TenantId != 1 || rh.Queue != "orders" {
+		t.Fatalf("unexpected decoded receipt handle: %+v", rh)
+	}
+}
+
+func TestReceiptHandleRejectsWrongTenant(t *testing.T) {
+	s := &SQS{receiptSecret: []byte("secret")}
+
+	handle := s.issueReceiptHandle(1, "orders", 42, 1, 30)
+
+	if _, err := s.verifyReceiptHandle(handle, 2, "orders"); err != errReceiptHandleInvalid {
+		t.Fatalf("expected errReceiptHandleInvalid for a mismatched tenant, got %v", err)
+	}
+}
+
+func TestReceiptHandleRejectsWrongQueue(t *testing.T) {
+	s := &SQS{receiptSecret: []byte("secret")}
+
+	handle := s.issueReceiptHandle(1, "orders", 42, 1, 30)
+
+	if _, err := s.verifyReceiptHandle(handle, 1, "other-queue"); err != errReceiptHandleInvalid {
+		t.Fatalf("expected errReceiptHandleInvalid for a mismatched queue, got %v", err)
+	}
+}
+
+func TestReceiptHandleRejectsExpiredLease(t *testing.T) {
+	s := &SQS{receiptSecret: []byte("secret")}
+
+	handle := s.issueReceiptHandle(1, "orders", 42, 1, -1)
+
+	if _, err := s.verifyReceiptHandle(handle, 1, "orders"); err != errReceiptHandleInvalid {
+		t.Fatalf("expected errReceiptHandleInvalid for an expired lease, got %v", err)
+	}
+}
+
+func TestReceiptHandleRejectsTamperedSignature(t *testing.T) {
+	s := &SQS{receiptSecret: []byte("secret")}
+
+	handle := s.issueReceiptHandle(1, "orders", 42, 1, 30)
+	replacement := "x"
+	if handle[len(handle)-1] == 'x' {
+		replacement = "y"
+	}
+	tampered := handle[:len(handle)-1] + replacement
+
+	if _, err := s.verifyReceiptHandle(tampered, 1, "orders"); err != errReceiptHandleInvalid {
+		t.Fatalf("expected errReceiptHandleInvalid for a tampered handle, got %v", err)
+	}
+}
+
+func TestReceiptHandleAcceptsPreviousSecretDuringRotation(t *testing.T) {
+	issuer := &SQS{receiptSecret: []byte("old-secret")}
+	handle := issuer.issueReceiptHandle(1, "orders", 42, 1, 30)
+
+	verifier := &SQS{receiptSecret: []byte("new-secret"), previousReceiptSecret: []byte("old-secret")}
+
+	rh, err := verifier.verifyReceiptHandle(handle, 1, "orders")
+	if err != nil {
+		t.Fatalf("expected a handle signed with the previous secret to verify, got %v", err)
+	}
+	if rh.MessageId != 42 {
+		t.Fatalf("unexpected decoded receipt handle: %+v", rh)
+	}
+}
+
+func TestReceiptHandleRejectsUnknownSecret(t *testing.T) {
+	issuer := &SQS{receiptSecret: []byte("old-secret")}
+	handle := issuer.issueReceiptHandle(1, "orders", 42, 1, 30)
+
+	verifier := &SQS{receiptSecret: []byte("new-secret")}
+
+	if _, err := verifier.verifyReceiptHandle(handle, 1, "orders"); err != errReceiptHandleInvalid {
+		t.Fatalf("expected errReceiptHandleInvalid once the old secret has fully rotated out, got %v", err)
+	}
+}
@@ -0,0 +1,78 @@
+package sqs
+
+import (
+	"encoding/json"
+	"q/models"
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type RegisterSchemaRequest struct {
+	Codec      string `json:"Codec"`
+	Definition string `json:"Definition"`
+}
+
+type RegisterSchemaResponse struct {
+	Subject string `json:"Subject"`
+	Version int    `json:"Version"`
+}
+
+// RegisterSchema adds a new version of subject to the schema registry.
+// POST /schemas/{subject}
+func (s *SQS) RegisterSchema(c *fiber.Ctx) error {
+	subject := c.Params("subject")
+
+	req := &RegisterSchemaRequest{}
+	err := json.Unmarshal(c.Body(), req)
+	if err != nil {
+		return err
+	}
+
+	tenantId := c.Locals("tenantId").(int64)
+
+	schema, err := s.schemas.Register(tenantId, subject, req.Codec, req.Definition)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(RegisterSchemaResponse{Subject: schema.Subject, Version: schema.Version})
+}
+
+type GetSchemaResponse struct {
+	Subject    string `json:"Subject"`
+	Version    int    `json:"Version"`
+	Codec      string `json:"Codec"`
+	Definition string `json:"Definition"`
+}
+
+// GetSchema returns a subject's schema, defaulting to the latest version.
+// GET /schemas/{subject}?version=N
+func (s *SQS) GetSchema(c *fiber.Ctx) error {
+	subject := c.Params("subject")
+	tenantId := c.Locals("tenantId").(int64)
+
+	var schema *models.Schema
+	var err error
+
+	if versionParam := c.Query("version"); versionParam != "" {
+		version, parseErr := strconv.Atoi(versionParam)
+		if parseErr != nil {
+			return parseErr
+		}
+		schema, err = s.schemas.Get(tenantId, subject, version)
+	} else {
+		schema, err = s.schemas.Latest(tenantId, subject)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(GetSchemaResponse{
+		Subject:    schema.Subject,
+		Version:    schema.Version,
+		Codec:      schema.Codec,
+		Definition: schema.Definition,
+	})
+}
@@ -16,6 +16,7 @@ aws sqs get-queue-attributes --queue-url https://sqs.us-east-1.amazonaws.com/1/a
 
 import (
 	"crypto/md5"
+	"crypto/sha256"
 	"encoding/base64"
 	"encoding/hex"
 	"encoding/json"
@@ -27,6 +28,7 @@ import (
 	"q/models"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/gofiber/contrib/fiberzerolog"
 	"github.com/gofiber/fiber/v2"
@@ -35,13 +37,28 @@ import (
 	"github.com/valyala/fasthttp/fasthttpadaptor"
 )
 
+// maxWaitTimeSeconds mirrors the AWS-imposed ceiling on ReceiveMessage long-polling.
+const maxWaitTimeSeconds = 20
+
+// defaultVisibilityTimeout applies when a queue has no VisibilityTimeout attribute set.
+const defaultVisibilityTimeout = 30
+
 type SQS struct {
 	app           *fiber.App
 	queue         models.Queue
 	tenantManager models.TenantManager
+	codecs        *models.CodecRegistry
+	schemas       models.SchemaRegistry
+
+	receiptSecret         []byte
+	previousReceiptSecret []byte
 }
 
-func NewSQS(queue models.Queue, tenantManager models.TenantManager) *SQS {
+// NewSQS wires up the SQS endpoint. receiptHandleSecret signs outgoing
+// ReceiptHandles; previousReceiptHandleSecret (optional, pass "" if unset)
+// is still accepted for verification so secrets can be rotated without
+// invalidating in-flight leases.
+func NewSQS(queue models.Queue, tenantManager models.TenantManager, codecs *models.CodecRegistry, schemas models.SchemaRegistry, receiptHandleSecret string, previousReceiptHandleSecret string) *SQS {
 	app := fiber.New(fiber.Config{DisableStartupMessage: true})
 
 	logger := zerolog.New(zerolog.ConsoleWriter{Out: os.Stderr}).With().Timestamp().Logger().Level(zerolog.ErrorLevel)
@@ -51,12 +68,18 @@ func NewSQS(queue models.Queue, tenantManager models.TenantManager) *SQS {
 	}))
 
 	s := &SQS{
-		app:           app,
-		queue:         queue,
-		tenantManager: tenantManager,
+		app:                   app,
+		queue:                 queue,
+		tenantManager:         tenantManager,
+		codecs:                codecs,
+		schemas:               schemas,
+		receiptSecret:         []byte(receiptHandleSecret),
+		previousReceiptSecret: []byte(previousReceiptHandleSecret),
 	}
 
 	app.Use(s.authMiddleware)
+	app.Post("/schemas/:subject", s.RegisterSchema)
+	app.Get("/schemas/:subject", s.GetSchema)
 	app.Post("/*", s.Action)
 
 	return s
@@ -117,12 +140,22 @@ func (s *SQS) Action(c *fiber.Ctx) error {
 		return s.ReceiveMessage(c, tenantId)
 	case "AmazonSQS.DeleteMessage":
 		return s.DeleteMessage(c, tenantId)
+	case "AmazonSQS.SendMessageBatch":
+		return s.SendMessageBatch(c, tenantId)
+	case "AmazonSQS.DeleteMessageBatch":
+		return s.DeleteMessageBatch(c, tenantId)
+	case "AmazonSQS.ChangeMessageVisibilityBatch":
+		return s.ChangeMessageVisibilityBatch(c, tenantId)
 	case "AmazonSQS.ListQueues":
 		return s.ListQueues(c, tenantId)
 	case "AmazonSQS.CreateQueue":
 		return s.CreateQueue(c, tenantId)
 	case "AmazonSQS.GetQueueAttributes":
 		return s.GetQueueAttributes(c, tenantId)
+	case "AmazonSQS.SetQueueAttributes":
+		return s.SetQueueAttributes(c, tenantId)
+	case "AmazonSQS.ChangeMessageVisibility":
+		return s.ChangeMessageVisibility(c, tenantId)
 	case "AmazonSQS.PurgeQueue":
 		return s.PurgeQueue(c, tenantId)
 	case "AmazonSQS.DeleteQueue":
@@ -188,10 +221,24 @@ func (s *SQS) GetQueueAttributes(c *fiber.Ctx, tenantId int64) error {
 
 	stats := s.queue.Stats(tenantId, queue)
 
+	attributes := s.queue.GetAttributes(tenantId, queue)
+	if attributes == nil {
+		attributes = make(map[string]string)
+	}
+	attributes["ApproximateNumberOfMessages"] = fmt.Sprintf("%d", stats.TotalMessages)
+
+	// FifoQueue only ever appears as "true" (AWS omits it for standard
+	// queues); reflect the name-suffix case even if the client never set
+	// the attribute explicitly.
+	if s.isFifoQueue(tenantId, queue) {
+		attributes["FifoQueue"] = "true"
+		if _, ok := attributes["ContentBasedDeduplication"]; !ok {
+			attributes["ContentBasedDeduplication"] = "false"
+		}
+	}
+
 	rc := GetQueueAttributesResponse{
-		Attributes: map[string]string{
-			"ApproximateNumberOfMessages": fmt.Sprintf("%d", stats.TotalMessages),
-		},
+		Attributes: attributes,
 	}
 
 	return c.JSON(rc)
@@ -205,7 +252,7 @@ func (s *SQS) CreateQueue(c *fiber.Ctx, tenantId int64) error {
 		return err
 	}
 
-	err = s.queue.CreateQueue(tenantId, req.QueueName)
+	err = s.queue.CreateQueue(tenantId, req.QueueName, req.Attributes)
 	if err != nil {
 		return err
 	}
@@ -238,9 +285,6 @@ func (s *SQS) ListQueues(c *fiber.Ctx, tenantId int64) error {
 }
 
 func (s *SQS) SendMessage(c *fiber.Ctx, tenantId int64) error {
-	// TODO: make this configurable on queue
-	visibilityTimeout := 30
-
 	req := &SendMessagePayload{}
 
 	err := json.Unmarshal(c.Body(), req)
@@ -251,6 +295,13 @@ func (s *SQS) SendMessage(c *fiber.Ctx, tenantId int64) error {
 	tokens := strings.Split(req.QueueUrl, "/")
 	queue := tokens[len(tokens)-1]
 
+	visibilityTimeout := defaultVisibilityTimeout
+	if v, ok := s.queue.GetAttributes(tenantId, queue)["VisibilityTimeout"]; ok {
+		if parsed, err := strconv.Atoi(v); err == nil {
+			visibilityTimeout = parsed
+		}
+	}
+
 	kv := make(map[string]string)
 	for k, v := range req.MessageAttributes {
 		kv[k+"_DataType"] = v.DataType
@@ -263,22 +314,61 @@ func (s *SQS) SendMessage(c *fiber.Ctx, tenantId int64) error {
 		}
 	}
 
+	hasher := md5.New()
+	hasher.Write([]byte(req.MessageBody))
+	md5OfBody := hex.EncodeToString(hasher.Sum(nil))
+
+	if s.isFifoQueue(tenantId, queue) {
+		dedupId := req.MessageDeduplicationId
+		if dedupId == "" {
+			if s.queue.GetAttributes(tenantId, queue)["ContentBasedDeduplication"] != "true" {
+				return errors.New("MissingParameter: The request must contain a non-empty value for MessageDeduplicationId, or the queue must have ContentBasedDeduplication enabled")
+			}
+			contentHash := sha256.Sum256([]byte(req.MessageBody))
+			dedupId = hex.EncodeToString(contentHash[:])
+		}
+
+		messageId, sequenceNumber, err := s.queue.EnqueueFifo(tenantId, queue, req.MessageBody, kv, req.DelaySeconds, visibilityTimeout, req.MessageGroupId, dedupId)
+		if err != nil {
+			return err
+		}
+
+		return c.JSON(SendMessageResponse{
+			MessageId:        fmt.Sprintf("%d", messageId),
+			MD5OfMessageBody: md5OfBody,
+			SequenceNumber:   sequenceNumber,
+		})
+	}
+
 	messageId, err := s.queue.Enqueue(tenantId, queue, req.MessageBody, kv, req.DelaySeconds, visibilityTimeout)
 	if err != nil {
 		return err
 	}
 
-	hasher := md5.New()
-	hasher.Write([]byte(req.MessageBody))
-
 	response := SendMessageResponse{
 		MessageId:        fmt.Sprintf("%d", messageId),
-		MD5OfMessageBody: hex.EncodeToString(hasher.Sum(nil)),
+		MD5OfMessageBody: md5OfBody,
 	}
 
 	return c.JSON(response)
 }
 
+// isFifoQueueName reports whether queue follows the FIFO queue naming
+// convention (a ".fifo" suffix, as required by SQS).
+func isFifoQueueName(queue string) bool {
+	return strings.HasSuffix(queue, ".fifo")
+}
+
+// isFifoQueue reports whether queue is a FIFO queue: either its name ends
+// in ".fifo", or it was created/configured with the FifoQueue attribute set
+// to "true". Either is sufficient to turn on FIFO semantics.
+func (s *SQS) isFifoQueue(tenantId int64, queue string) bool {
+	if isFifoQueueName(queue) {
+		return true
+	}
+	return s.queue.GetAttributes(tenantId, queue)["FifoQueue"] == "true"
+}
+
 type DeleteQueueRequest struct {
 	QueueUrl string `json:"QueueUrl"`
 }
@@ -302,11 +392,28 @@ func (s *SQS) ReceiveMessage(c *fiber.Ctx, tenantId int64) error {
 	tokens := strings.Split(req.QueueUrl, "/")
 	queue := tokens[len(tokens)-1]
 
+	visibilityTimeout := defaultVisibilityTimeout
+	if v, ok := s.queue.GetAttributes(tenantId, queue)["VisibilityTimeout"]; ok {
+		if parsed, err := strconv.Atoi(v); err == nil {
+			visibilityTimeout = parsed
+		}
+	}
+	if req.VisibilityTimeout > 0 {
+		visibilityTimeout = req.VisibilityTimeout
+	}
+
 	messages, err := s.queue.Dequeue(tenantId, queue, req.MaxNumberOfMessages)
 	if err != nil {
 		return err
 	}
 
+	if len(messages) == 0 && req.WaitTimeSeconds > 0 {
+		messages, err = s.longPollDequeue(c, tenantId, queue, req.MaxNumberOfMessages, req.WaitTimeSeconds)
+		if err != nil {
+			return err
+		}
+	}
+
 	response := ReceiveMessageResponse{
 		Messages: make([]Message, len(messages)),
 	}
@@ -319,10 +426,13 @@ func (s *SQS) ReceiveMessage(c *fiber.Ctx, tenantId int64) error {
 
 		response.Messages[i] = Message{
 			MessageId:         fmt.Sprintf("%d", message.ID),
-			ReceiptHandle:     fmt.Sprintf("%d", message.ID),
+			ReceiptHandle:     s.issueReceiptHandle(tenantId, queue, message.ID, message.ReceiveCount, visibilityTimeout),
 			Body:              string(message.Message),
 			MessageAttributes: make(map[string]MessageAttribute),
 			MD5OfBody:         hex.EncodeToString(hasher.Sum(nil)),
+			Attributes: map[string]string{
+				"ApproximateReceiveCount": fmt.Sprintf("%d", message.ReceiveCount),
+			},
 		}
 
 		for k, v := range message.KeyValues {
@@ -352,6 +462,50 @@ func (s *SQS) ReceiveMessage(c *fiber.Ctx, tenantId int64) error {
 	return c.JSON(response)
 }
 
+// longPollDequeue blocks until a message becomes available for tenantId/queue,
+// the wait deadline expires, or the server shuts down (c.Context().Done() is
+// only closed on shutdown), whichever happens first. A client disconnect is
+// not detected; the wait then runs on until a message or the deadline.
+func (s *SQS) longPollDequeue(c *fiber.Ctx, tenantId int64, queue string, maxMessages, waitTimeSeconds int) ([]models.Message, error) {
+	if waitTimeSeconds > maxWaitTimeSeconds {
+		waitTimeSeconds = maxWaitTimeSeconds
+	}
+
+	notify, cancel := s.queue.Subscribe(tenantId, queue)
+	defer cancel()
+
+	// A message may already have become visible (or been enqueued) between
+	// the caller's own Dequeue and the Subscribe above; check once more
+	// before waiting so that case isn't only resolved by some later,
+	// unrelated enqueue rebroadcasting notify.
+	if messages, err := s.queue.Dequeue(tenantId, queue, maxMessages); err != nil {
+		return nil, err
+	} else if len(messages) > 0 {
+		return messages, nil
+	}
+
+	deadline := make(chan struct{})
+	timer := time.AfterFunc(time.Duration(waitTimeSeconds)*time.Second, func() { close(deadline) })
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-notify:
+			messages, err := s.queue.Dequeue(tenantId, queue, maxMessages)
+			if err != nil {
+				return nil, err
+			}
+			if len(messages) > 0 {
+				return messages, nil
+			}
+		case <-deadline:
+			return nil, nil
+		case <-c.Context().Done():
+			return nil, nil
+		}
+	}
+}
+
 func (s *SQS) DeleteMessage(c *fiber.Ctx, tenantId int64) error {
 	req := &DeleteMessageRequest{}
 
@@ -363,12 +517,12 @@ func (s *SQS) DeleteMessage(c *fiber.Ctx, tenantId int64) error {
 	tokens := strings.Split(req.QueueUrl, "/")
 	queue := tokens[len(tokens)-1]
 
-	messageId, err := strconv.ParseInt(req.ReceiptHandle, 10, 64)
+	rh, err := s.verifyReceiptHandle(req.ReceiptHandle, tenantId, queue)
 	if err != nil {
 		return err
 	}
 
-	err = s.queue.Delete(tenantId, queue, messageId)
+	err = s.queue.DeleteLeased(tenantId, queue, rh.MessageId, rh.ReceiveCount)
 	if err != nil {
 		return err
 	}
@@ -0,0 +1,88 @@
+package sqs
+
+import (
+	"encoding/json"
+	"testing"
+
+	"q/models"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func TestIsFifoQueueChecksNameSuffixOrAttribute(t *testing.T) {
+	queue := models.NewMemoryQueue()
+	if err := queue.CreateQueue(1, "orders.fifo", nil); err != nil {
+		t.Fatalf("CreateQueue: %v", err)
+	}
+	if err := queue.CreateQueue(1, "orders-attr", map[string]string{"FifoQueue": "true"}); err != nil {
+		t.Fatalf("CreateQueue: %v", err)
+	}
+	if err := queue.CreateQueue(1, "orders-plain", nil); err != nil {
+		t.Fatalf("CreateQueue: %v", err)
+	}
+
+	s := &SQS{queue: queue}
+
+	if !s.isFifoQueue(1, "orders.fifo") {
+		t.Fatal("expected a .fifo-suffixed queue to be FIFO")
+	}
+	if !s.isFifoQueue(1, "orders-attr") {
+		t.Fatal("expected a queue created with FifoQueue=true to be FIFO despite lacking the .fifo suffix")
+	}
+	if s.isFifoQueue(1, "orders-plain") {
+		t.Fatal("expected a plain queue with neither signal to not be FIFO")
+	}
+}
+
+func TestSendMessageHonorsFifoQueueAttribute(t *testing.T) {
+	queue := models.NewMemoryQueue()
+	if err := queue.CreateQueue(1, "orders", map[string]string{"FifoQueue": "true"}); err != nil {
+		t.Fatalf("CreateQueue: %v", err)
+	}
+
+	app, _ := newTestApp(t, queue, func(s *SQS) func(c *fiber.Ctx, tenantId int64) error {
+		return s.SendMessage
+	})
+
+	respBody := doPost(t, app, SendMessagePayload{
+		QueueUrl:               "https://sqs.us-east-1.amazonaws.com/1/orders",
+		MessageBody:            "hello",
+		MessageGroupId:         "group-a",
+		MessageDeduplicationId: "dedup-1",
+	})
+
+	var resp SendMessageResponse
+	if err := json.Unmarshal(respBody, &resp); err != nil {
+		t.Fatalf("unmarshal response: %v, body=%s", err, respBody)
+	}
+	if resp.SequenceNumber == "" {
+		t.Fatalf("expected a SequenceNumber for a FIFO send against a FifoQueue-attribute queue, got %+v", resp)
+	}
+}
+
+func TestGetQueueAttributesReportsFifoQueueFromNameSuffix(t *testing.T) {
+	queue := models.NewMemoryQueue()
+	if err := queue.CreateQueue(1, "orders.fifo", nil); err != nil {
+		t.Fatalf("CreateQueue: %v", err)
+	}
+
+	app, _ := newTestApp(t, queue, func(s *SQS) func(c *fiber.Ctx, tenantId int64) error {
+		return s.GetQueueAttributes
+	})
+
+	respBody := doPost(t, app, GetQueueAttributesRequest{
+		QueueUrl: "https://sqs.us-east-1.amazonaws.com/1/orders.fifo",
+	})
+
+	var resp GetQueueAttributesResponse
+	if err := json.Unmarshal(respBody, &resp); err != nil {
+		t.Fatalf("unmarshal response: %v, body=%s", err, respBody)
+	}
+
+	if resp.Attributes["FifoQueue"] != "true" {
+		t.Fatalf("expected GetQueueAttributes to report FifoQueue=true for a .fifo-suffixed queue, got %+v", resp.Attributes)
+	}
+	if resp.Attributes["ContentBasedDeduplication"] != "false" {
+		t.Fatalf("expected a default ContentBasedDeduplication=false, got %+v", resp.Attributes)
+	}
+}
@@ -0,0 +1,69 @@
+package sqs
+
+import (
+	"encoding/json"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type ChangeMessageVisibilityRequest struct {
+	QueueUrl          string `json:"QueueUrl"`
+	ReceiptHandle     string `json:"ReceiptHandle"`
+	VisibilityTimeout int    `json:"VisibilityTimeout"`
+}
+
+type ChangeMessageVisibilityResponse struct{}
+
+type SetQueueAttributesRequest struct {
+	QueueUrl   string            `json:"QueueUrl"`
+	Attributes map[string]string `json:"Attributes"`
+}
+
+type SetQueueAttributesResponse struct{}
+
+func (s *SQS) ChangeMessageVisibility(c *fiber.Ctx, tenantId int64) error {
+	req := &ChangeMessageVisibilityRequest{}
+
+	err := json.Unmarshal(c.Body(), req)
+	if err != nil {
+		return err
+	}
+
+	tokens := strings.Split(req.QueueUrl, "/")
+	queue := tokens[len(tokens)-1]
+
+	rh, err := s.verifyReceiptHandle(req.ReceiptHandle, tenantId, queue)
+	if err != nil {
+		return err
+	}
+
+	err = s.queue.ChangeVisibilityLeased(tenantId, queue, rh.MessageId, rh.ReceiveCount, req.VisibilityTimeout)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(ChangeMessageVisibilityResponse{})
+}
+
+// SetQueueAttributes persists VisibilityTimeout, MessageRetentionPeriod,
+// DelaySeconds, MaximumMessageSize, and RedrivePolicy (among others) the same
+// way CreateQueue does, so GetQueueAttributes reflects them afterwards.
+func (s *SQS) SetQueueAttributes(c *fiber.Ctx, tenantId int64) error {
+	req := &SetQueueAttributesRequest{}
+
+	err := json.Unmarshal(c.Body(), req)
+	if err != nil {
+		return err
+	}
+
+	tokens := strings.Split(req.QueueUrl, "/")
+	queue := tokens[len(tokens)-1]
+
+	err = s.queue.SetAttributes(tenantId, queue, req.Attributes)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(SetQueueAttributesResponse{})
+}